@@ -0,0 +1,32 @@
+package deadcode
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// checkCountBaseline compares count with the one stored in filename and
+// fails when it increased. The file is created when it does not exist and
+// rewritten when update is set.
+func checkCountBaseline(filename string, count int, update bool) error {
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) || update {
+		return os.WriteFile(filename, []byte(strconv.Itoa(count)+"\n"), 0o644)
+	}
+	if err != nil {
+		return fmt.Errorf("read count baseline: %v", err)
+	}
+
+	baseline, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return fmt.Errorf("parse count baseline %s: %v", filename, err)
+	}
+
+	if count > baseline {
+		return fmt.Errorf("dead code increased: %d issues, baseline %d", count, baseline)
+	}
+	return nil
+}
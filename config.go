@@ -0,0 +1,73 @@
+package deadcode
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configNames are the names of the project config file.
+var configNames = []string{".deadcode.yml", ".deadcode.yaml"}
+
+// withConfig returns settings merged over the project config file, if any.
+func withConfig(settings any) (any, error) {
+	filename := findConfig(cwd)
+	if filename == "" {
+		return settings, nil
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("read config: %v", err)
+	}
+
+	config := make(map[string]any)
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("parse config %s: %v", filename, err)
+	}
+
+	switch settings := settings.(type) {
+	case nil:
+	case map[string]any:
+		maps.Copy(config, settings)
+	default:
+		return settings, nil
+	}
+	return config, nil
+}
+
+// findConfig returns the project config file of dir or of its closest
+// parent, without going past the root of the module or repository.
+func findConfig(dir string) string {
+	for {
+		for _, name := range configNames {
+			filename := filepath.Join(dir, name)
+			if _, err := os.Stat(filename); err == nil {
+				return filename
+			}
+		}
+
+		if isBoundary(dir) {
+			return ""
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// isBoundary reports whether dir is the root of a module or repository.
+func isBoundary(dir string) bool {
+	for _, name := range []string{"go.mod", ".git"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
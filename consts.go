@@ -0,0 +1,108 @@
+package deadcode
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// constDecl is a package-level constant.
+type constDecl struct {
+	name    string
+	pkgpath string
+	pkgname string
+	posn    token.Position
+	lines   int
+}
+
+// constUse is a use of a package-level constant.
+type constUse struct {
+	fn   string         // name of the enclosing func, "" outside funcs
+	posn token.Position // of the enclosing func
+}
+
+// constDecls returns the package-level constants declared in file, except
+// in groups whose values depend on the order of the constants, where
+// deleting one would change the others.
+func constDecls(p *packages.Package, file *ast.File) []constDecl {
+	var consts []constDecl
+	for _, decl := range file.Decls {
+		decl, ok := decl.(*ast.GenDecl)
+		if !ok || decl.Tok != token.CONST || ordered(decl) {
+			continue
+		}
+
+		for _, spec := range decl.Specs {
+			spec := spec.(*ast.ValueSpec)
+			for _, name := range spec.Names {
+				if name.Name == "_" {
+					continue
+				}
+
+				c := constDecl{
+					name:    name.Name,
+					pkgpath: p.PkgPath,
+					pkgname: p.Name,
+					posn:    p.Fset.Position(name.Pos()),
+					lines:   declLines(p.Fset, spec.Doc, spec),
+				}
+				if len(decl.Specs) == 1 && len(spec.Names) == 1 {
+					c.lines = declLines(p.Fset, decl.Doc, decl)
+				}
+				consts = append(consts, c)
+			}
+		}
+	}
+	return consts
+}
+
+// ordered reports whether the values of the constants of decl depend on
+// their order: they use iota, or repeat the previous values implicitly.
+func ordered(decl *ast.GenDecl) bool {
+	for _, spec := range decl.Specs {
+		spec := spec.(*ast.ValueSpec)
+		if len(spec.Values) == 0 {
+			return true
+		}
+
+		for _, value := range spec.Values {
+			found := false
+			ast.Inspect(value, func(n ast.Node) bool {
+				if id, ok := n.(*ast.Ident); ok && id.Name == "iota" {
+					found = true
+				}
+				return !found
+			})
+			if found {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// constUses adds to uses the uses of package-level constants in file, by
+// position of the constant.
+func constUses(p *packages.Package, file *ast.File, uses map[token.Position][]constUse) {
+	for _, decl := range file.Decls {
+		var use constUse
+		if decl, ok := decl.(*ast.FuncDecl); ok {
+			use = constUse{fn: decl.Name.Name, posn: p.Fset.Position(decl.Name.Pos())}
+		}
+
+		ast.Inspect(decl, func(n ast.Node) bool {
+			id, ok := n.(*ast.Ident)
+			if !ok {
+				return true
+			}
+
+			if obj, ok := p.TypesInfo.Uses[id].(*types.Const); ok && obj.Pkg() != nil && obj.Parent() == obj.Pkg().Scope() {
+				posn := p.Fset.Position(obj.Pos())
+				uses[posn] = append(uses[posn], use)
+			}
+			return true
+		})
+	}
+}
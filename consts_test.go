@@ -0,0 +1,13 @@
+package deadcode
+
+import "testing"
+
+func TestConsts(t *testing.T) {
+	got := analyze(t, "consts", Settings{Consts: true})
+	assertIssues(t, got,
+		"main.go:15: const `one` is unused",
+		"main.go:19: const `unused` is unused",
+		"main.go:21: const `onlyDead` is unused: only used by unused dead",
+		"main.go:25: func `dead` is unused",
+	)
+}
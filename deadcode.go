@@ -1,17 +1,33 @@
 package deadcode
 
 import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"go/ast"
+	"go/build/constraint"
+	"go/parser"
 	"go/token"
 	"go/types"
 	"maps"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/golangci/plugin-module-register/register"
+	"golang.org/x/sync/errgroup"
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/callgraph/rta"
 	"golang.org/x/tools/go/packages"
@@ -19,6 +35,9 @@ import (
 	"golang.org/x/tools/go/ssa/ssautil"
 )
 
+// Version of the linter.
+const Version = "v0.35.0"
+
 var cwd, _ = os.Getwd()
 
 // DeadCode instance linter.
@@ -28,15 +47,196 @@ type DeadCode struct {
 
 // Issue from linter.
 type Issue struct {
-	Func     string
-	Filename string
-	Line     int
+	ID         string // stable across unrelated edits, if requested
+	Kind       Kind
+	Package    string
+	PkgName    string
+	Recv       string
+	Func       string
+	Filename   string
+	Line       int
+	Confidence Confidence
+	Notes      []string
+	Doc        string
+	Hint       string // how to fix it, in verbose mode
+	Lines      int    // deleted with the declaration, doc comment included
+	Suppressed string // reason of the suppression, if reported anyway
 }
 
+// Kind of unused declaration.
+type Kind string
+
+// Kinds of issues.
+const (
+	KindFunc    Kind = "func"
+	KindMethod  Kind = "method"
+	KindFuncVar Kind = "func-var"
+	KindFile    Kind = "file"
+	KindConst   Kind = "const"
+	KindPackage Kind = "package"
+)
+
+// Confidence of an issue.
+type Confidence string
+
+// Confidence levels.
+const (
+	ConfidenceHigh Confidence = "high"
+	ConfidenceLow  Confidence = "low"
+)
+
 // Settings linter.
 type Settings struct {
 	Test   bool   `json:"test"`
 	Filter string `json:"filter"`
+
+	// Patterns are the packages to analyze, "./..." by default. Import
+	// paths outside the current module are resolved by the go command.
+	Patterns []string `json:"patterns"`
+
+	// FailOnEmpty fails when no function of the packages to report is
+	// analyzed, which usually means patterns or filter are misconfigured.
+	FailOnEmpty bool `json:"fail-on-empty"`
+
+	// GoExperiment is the GOEXPERIMENT value used to load packages.
+	GoExperiment string `json:"goexperiment"`
+
+	// Binary is the import path of the only main package to analyze
+	// reachability from, instead of all of them.
+	Binary string `json:"binary"`
+
+	// TestTags are build tags gating test-support code: files constrained
+	// by them are loaded and their exported functions are roots.
+	TestTags []string `json:"test-tags"`
+
+	// PluginSymbols are symbols looked up in plugins built from the analyzed
+	// main packages, in addition to the constant arguments of
+	// (*plugin.Plugin).Lookup calls: the functions they name are roots.
+	PluginSymbols []string `json:"plugin-symbols"`
+
+	// LibraryInternal treats the exported API of non-main packages as
+	// used, and reports their unexported functions unreachable from it.
+	LibraryInternal bool `json:"library-internal"`
+
+	// AsmReachable treats the functions declared without body in packages
+	// with assembly files as roots.
+	AsmReachable bool `json:"asm-reachable"`
+
+	// RootPackagePrefixes are import paths of packages whose exported API,
+	// and the one of the packages below them, is treated as used, like a
+	// public API of the module.
+	RootPackagePrefixes []string `json:"root-package-prefixes"`
+
+	// ExportedInterfaces treats methods implementing an exported interface
+	// of a non-main package as reachable, since code outside the module
+	// may call them through it.
+	ExportedInterfaces bool `json:"exported-interfaces"`
+
+	// Issues are suppressed, in order of precedence, by a //deadcode:ignore
+	// directive in their doc comment, by Whitelist (names as `Func`,
+	// `Type.Method`, or qualified by the package path) and ExcludeReceivers
+	// (types whose methods are never reported), when Exclude
+	// matches their package path or a line of ExcludePathsFile their file
+	// path, and by the Baseline file.
+	// UpdateBaseline records the current issues in the Baseline file.
+	Whitelist        []string `json:"whitelist"`
+	ExcludeReceivers []string `json:"exclude-receivers"`
+	Exclude          string   `json:"exclude"`
+	ExcludePathsFile string   `json:"exclude-paths-file"`
+	Baseline         string   `json:"baseline"`
+	UpdateBaseline   bool     `json:"update-baseline"`
+
+	// Limit is the maximum number of issues reported, if positive. The
+	// number of issues left out is printed to stderr.
+	Limit int `json:"limit"`
+
+	// ShowIDs prefixes issues with a short ID derived from their package
+	// and qualified name, to track them across runs.
+	ShowIDs bool `json:"show-ids"`
+
+	// ReportSuppressed reports suppressed issues too, marked with the
+	// reason of their suppression, to review stale suppressions.
+	ReportSuppressed bool `json:"report-suppressed"`
+
+	// CountBaseline is a file storing the number of issues: the linter fails
+	// when it increases. UpdateCountBaseline records the current number.
+	CountBaseline       string `json:"count-baseline"`
+	UpdateCountBaseline bool   `json:"update-count-baseline"`
+
+	// Doc adds the doc comment of unused functions to issues.
+	Doc bool `json:"doc"`
+
+	// Shadowed notes unused functions whose name is declared locally in
+	// their package, so that references resolve to the local instead.
+	Shadowed bool `json:"shadowed"`
+
+	// StrictVars reports functions only referenced from the values of
+	// package-level variables that are never read.
+	StrictVars bool `json:"strict-vars"`
+
+	// Consts reports package-level constants only used by unreachable
+	// functions, or not used at all.
+	Consts bool `json:"consts"`
+
+	// DeadFiles reports files of which every declaration is unused as a
+	// whole, instead of each declaration.
+	DeadFiles bool `json:"dead-files"`
+
+	// DeadPackages reports packages not imported by the analyzed program as
+	// a whole, instead of each declaration.
+	DeadPackages bool `json:"dead-packages"`
+
+	// Concurrency bounds the number of packages whose issues are built in
+	// parallel, GOMAXPROCS by default.
+	Concurrency int `json:"concurrency"`
+
+	// ListEntrypoints prints the roots of the analysis to stderr.
+	ListEntrypoints bool `json:"list-entrypoints"`
+
+	// DumpSourceFuncs prints every function considered by the analysis
+	// with its position and reachability to stderr.
+	DumpSourceFuncs bool `json:"dump-source-funcs"`
+
+	// DeletionOrder prints the unused functions to stderr, each before the
+	// unused functions it references.
+	DeletionOrder bool `json:"deletion-order"`
+
+	// CPUProfile and MemProfile are files to write pprof profiles of the
+	// analysis to.
+	CPUProfile string `json:"cpu-profile"`
+	MemProfile string `json:"mem-profile"`
+
+	// Progress prints the phases of the analysis to stderr.
+	Progress bool `json:"progress"`
+
+	// Stats prints analysis metadata to stderr.
+	Stats bool `json:"stats"`
+
+	// Verbose adds notes explaining the likely cause of an issue, and a
+	// hint of how to fix it.
+	Verbose bool `json:"verbose"`
+
+	// DowngradeConventionalMethods lowers the confidence of unused methods
+	// whose name is in ConventionalMethods, as they usually implement an
+	// interface and are called dynamically.
+	DowngradeConventionalMethods bool     `json:"downgrade-conventional-methods"`
+	ConventionalMethods          []string `json:"conventional-methods"`
+
+	// Overlay maps absolute file paths to contents replacing the ones on
+	// disk, e.g. unsaved editor buffers. It is only set through Analyze.
+	Overlay map[string][]byte `json:"-"`
+}
+
+// defaultConventionalMethods are method names of well-known interfaces.
+var defaultConventionalMethods = []string{
+	"ServeHTTP",
+	"Read",
+	"Write",
+	"Close",
+	"String",
+	"Error",
+	"MarshalJSON",
+	"UnmarshalJSON",
 }
 
 func init() {
@@ -45,16 +245,41 @@ func init() {
 
 // NewDeadCode retuns new instance linter.
 func NewDeadCode(settings any) (register.LinterPlugin, error) {
+	settings, err := withConfig(settings)
+	if err != nil {
+		return nil, err
+	}
+
 	s, err := register.DecodeSettings[Settings](settings)
 	if err != nil {
 		return nil, err
 	}
 
-	issues, err := runAnalysis(s)
+	issues, err := Analyze(s)
 	if err != nil {
 		return nil, err
 	}
 
+	if s.CountBaseline != "" {
+		count := 0
+		for _, issue := range issues {
+			if issue.Suppressed == "" {
+				count++
+			}
+		}
+
+		if err := checkCountBaseline(s.CountBaseline, count, s.UpdateCountBaseline); err != nil {
+			return nil, err
+		}
+	}
+
+	// Reported issues come first, sorted by position, so the ones kept
+	// don't depend on the run.
+	if s.Limit > 0 && len(issues) > s.Limit {
+		fmt.Fprintf(os.Stderr, "deadcode: …and %d more issues\n", len(issues)-s.Limit)
+		issues = issues[:s.Limit]
+	}
+
 	return &DeadCode{issues}, nil
 }
 
@@ -77,19 +302,30 @@ func (d *DeadCode) run(pass *analysis.Pass) (any, error) {
 			}
 
 			ast.Inspect(file, func(n ast.Node) bool {
-				funcDecl, ok := n.(*ast.FuncDecl)
-				if !ok {
-					return true
+				var pos token.Pos
+				switch n := n.(type) {
+				case *ast.FuncDecl:
+					if issue.Kind == KindFunc || issue.Kind == KindMethod {
+						pos = n.Pos()
+					}
+				case *ast.File:
+					if issue.Kind == KindFile || issue.Kind == KindPackage {
+						pos = n.Package
+					}
+				case *ast.Ident:
+					if (issue.Kind == KindFuncVar || issue.Kind == KindConst) && n.Name == issue.Func {
+						pos = n.Pos()
+					}
 				}
 
-				funcDeclPos := pass.Fset.Position(funcDecl.Pos())
-				if funcDeclPos.Line == issue.Line {
+				if pos.IsValid() && pass.Fset.Position(pos).Line == issue.Line {
 					pass.Report(analysis.Diagnostic{
-						Pos:            funcDecl.Pos(),
+						Pos:            pos,
 						End:            0,
-						Message:        fmt.Sprintf("func `%s` is unused", issue.Func),
+						Message:        issue.message(),
 						SuggestedFixes: nil,
 					})
+					return false
 				}
 
 				return true
@@ -99,21 +335,128 @@ func (d *DeadCode) run(pass *analysis.Pass) (any, error) {
 	return nil, nil
 }
 
+func (i Issue) message() string {
+	var message string
+	switch i.Kind {
+	case KindMethod:
+		message = fmt.Sprintf("method `%s` is unused", i.name())
+	case KindFuncVar:
+		message = fmt.Sprintf("func var `%s` is unused", i.Func)
+	case KindFile:
+		message = fmt.Sprintf("file `%s` is unused", i.Func)
+	case KindConst:
+		message = fmt.Sprintf("const `%s` is unused", i.Func)
+	case KindPackage:
+		message = fmt.Sprintf("package `%s` is unused", i.Package)
+	default:
+		message = fmt.Sprintf("func `%s` is unused", i.Func)
+	}
+	if i.Confidence == ConfidenceLow {
+		message += " (low confidence)"
+	}
+	if len(i.Notes) > 0 {
+		message += ": " + strings.Join(i.Notes, "; ")
+	}
+	if i.Doc != "" {
+		message += fmt.Sprintf(" (doc: %s)", i.Doc)
+	}
+	if i.Hint != "" {
+		message += fmt.Sprintf(" (hint: %s)", i.Hint)
+	}
+	if i.Suppressed != "" {
+		message += fmt.Sprintf(" (suppressed: %s)", i.Suppressed)
+	}
+	if i.ID != "" {
+		message = fmt.Sprintf("[%s] %s", i.ID, message)
+	}
+	return message
+}
+
+// hint returns how to fix the issue, depending on its kind and confidence.
+func (i Issue) hint() string {
+	switch {
+	case i.Kind == KindMethod && i.Confidence == ConfidenceLow:
+		return "may be called dynamically, through an interface or reflection: verify before deleting"
+	case i.Kind == KindMethod:
+		return "delete the method"
+	case i.Kind == KindFuncVar:
+		return "delete the variable and its func literal"
+	case i.Kind == KindFile:
+		return "delete the file"
+	case i.Kind == KindConst:
+		return "delete the constant"
+	case i.Kind == KindPackage:
+		return "delete the package, unless other modules import it"
+	default:
+		return "delete the function"
+	}
+}
+
+// name returns the name of the unused declaration, qualified by its receiver.
+func (i Issue) name() string {
+	if i.Recv != "" {
+		return i.Recv + "." + i.Func
+	}
+	return i.Func
+}
+
+// key returns a position-independent identifier of the issue.
+func (i Issue) key() string {
+	if i.Kind == KindPackage {
+		return i.Package
+	}
+	return i.Package + "." + i.name()
+}
+
+// id returns a short hash of the kind and key of the issue.
+func (i Issue) id() string {
+	sum := sha256.Sum256([]byte(string(i.Kind) + " " + i.key()))
+	return hex.EncodeToString(sum[:4])
+}
+
 func (d *DeadCode) GetLoadMode() string {
 	return register.LoadModeSyntax
 }
 
+// Analyze runs the analysis outside golangci-lint, e.g. from an editor
+// integration, and returns the issues sorted by position. Suppressed issues
+// are included, marked, when ReportSuppressed is set.
+func Analyze(settings Settings) ([]Issue, error) {
+	return profile(settings, runAnalysis)
+}
+
 func runAnalysis(settings Settings) ([]Issue, error) {
+	start := time.Now()
+	progress := progress{enabled: settings.Progress, start: start}
 	testFlag := settings.Test
 	filterFlag := settings.Filter
 
 	// Load, parse, and type-check the complete program(s).
 	cfg := &packages.Config{
-		Mode:  packages.LoadAllSyntax | packages.NeedModule,
-		Tests: testFlag,
+		Mode:    packages.LoadAllSyntax | packages.NeedModule,
+		Tests:   testFlag,
+		Overlay: settings.Overlay,
+	}
+
+	if settings.GoExperiment != "" {
+		cfg.Env = append(os.Environ(), "GOEXPERIMENT="+settings.GoExperiment)
+	}
+
+	testTags := make(map[string]bool)
+	if len(settings.TestTags) > 0 {
+		cfg.BuildFlags = append(cfg.BuildFlags, "-tags="+strings.Join(settings.TestTags, ","))
+		for _, tag := range settings.TestTags {
+			testTags[tag] = true
+		}
+	}
+
+	patterns := settings.Patterns
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
 	}
 
-	initial, err := packages.Load(cfg, "./...")
+	progress.phase("loading packages %v...", patterns)
+	initial, err := packages.Load(cfg, patterns...)
 	if err != nil {
 		return nil, fmt.Errorf("load: %v", err)
 	}
@@ -126,22 +469,61 @@ func runAnalysis(settings Settings) ([]Issue, error) {
 		return nil, errors.New("packages contain errors")
 	}
 
+	conventional := make(map[string]bool)
+	if settings.DowngradeConventionalMethods {
+		names := settings.ConventionalMethods
+		if len(names) == 0 {
+			names = defaultConventionalMethods
+		}
+		for _, name := range names {
+			conventional[name] = true
+		}
+	}
+
+	sup, err := newSuppressor(settings)
+	if err != nil {
+		return nil, err
+	}
+
 	var filter *regexp.Regexp
 
-	// If -filter is unset, use first module (if available).
+	// If -filter is unset, use the modules of the initial packages, so that
+	// dependencies (including go.mod tool dependencies) are not reported.
 	if filterFlag != "" {
 		filter, err = regexp.Compile(filterFlag)
 		if err != nil {
 			return nil, fmt.Errorf("failed create filter: %v", err)
 		}
+	} else if modules := initialModules(initial); len(modules) > 0 {
+		filter = regexp.MustCompile(`^(` + strings.Join(modules, "|") + `)(/|$)`)
 	}
 
 	// Create SSA-form program representation and find main packages.
+	progress.phase("building SSA of %d packages and their dependencies...", len(initial))
 	prog, pkgs := ssautil.AllPackages(initial, ssa.InstantiateGenerics)
 	prog.Build()
 
+	// In test mode, test functions are roots too (see below), and in library
+	// mode the exported API, so that libraries without main packages can be
+	// analyzed.
 	mains := ssautil.MainPackages(pkgs)
-	if len(mains) == 0 {
+
+	// The other binaries are not analyzed, so their packages are not
+	// reported.
+	binaries := make(map[string]bool)
+	if settings.Binary != "" {
+		mains = slices.DeleteFunc(mains, func(main *ssa.Package) bool {
+			if main.Pkg.Path() == settings.Binary {
+				return false
+			}
+			binaries[main.Pkg.Path()] = true
+			return true
+		})
+		if len(mains) == 0 {
+			return nil, fmt.Errorf("no find main package %s", settings.Binary)
+		}
+	}
+	if len(mains) == 0 && !testFlag && !settings.LibraryInternal {
 		return nil, errors.New("no find main packages")
 	}
 
@@ -150,26 +532,174 @@ func runAnalysis(settings Settings) ([]Issue, error) {
 		roots = append(roots, main.Func("init"), main.Func("main"))
 	}
 
+	if settings.ExportedInterfaces {
+		roots = append(roots, interfaceMethods(prog, pkgs, exportedInterfaces(pkgs))...)
+	}
+
+	roots = append(roots, providedRoots(prog, pkgs)...)
+
+	// Packages of the public API, whose exported declarations are used by
+	// definition.
+	apis := make(map[string]bool)
+	for _, pkg := range pkgs {
+		if pkg != nil && hasPathPrefix(pkg.Pkg.Path(), settings.RootPackagePrefixes) {
+			apis[pkg.Pkg.Path()] = true
+			roots = append(roots, exportedAPI(prog, pkg)...)
+		}
+	}
+
+	if settings.LibraryInternal {
+		for _, pkg := range pkgs {
+			if pkg != nil && pkg.Pkg.Name() != "main" {
+				apis[pkg.Pkg.Path()] = true
+				roots = append(roots, pkg.Func("init"))
+				roots = append(roots, exportedAPI(prog, pkg)...)
+			}
+		}
+	}
+
 	// Gather all source-level functions as the user interface is expressed in terms of them.
 	var sourceFuncs []*ssa.Function
+	var sourceFuncVars []funcVar
+	var sourceConsts []constDecl
+	vars := newStorage()
+	uses := make(map[token.Position][]constUse)
+	assigned := make(map[token.Position]bool)
+	reads := make(map[token.Position][]token.Position)
 	generated := make(map[string]bool)
+	files := make(map[string]sourceFile)
+	srcPkgs := make(map[string]sourcePackage)
+	blank := make(map[string]bool)
+	symbols := make(map[string]bool)
+	for _, name := range settings.PluginSymbols {
+		symbols[name] = true
+	}
+	docs := make(map[token.Position]string)
+	lines := make(map[token.Position]int)
+	ignored := make(map[string]map[string][]string)
+	shadows := make(map[string]map[string]token.Position)
+	genRefs := make(map[token.Position][]string)
+	var npkgs int
 	packages.Visit(initial, nil, func(p *packages.Package) {
+		npkgs++
+
+		if settings.Verbose && len(p.IgnoredFiles) > 0 {
+			ignored[p.PkgPath] = ignoredRefs(p.IgnoredFiles)
+		}
+
+		if settings.Shadowed {
+			shadows[p.PkgPath] = localDecls(p, shadows[p.PkgPath])
+		}
+
+		// Packages of the public API are used by definition.
+		if settings.DeadPackages && p.Name != "main" && (filter == nil || filter.MatchString(p.PkgPath)) && !apis[p.PkgPath] {
+			if _, ok := srcPkgs[p.PkgPath]; !ok {
+				if pkg, ok := newSourcePackage(p); ok {
+					srcPkgs[p.PkgPath] = pkg
+				}
+			}
+		}
+
 		for _, file := range p.Syntax {
+			// Skip the shims generated by cgo (_cgo_gotypes.go etc.): the
+			// Go files of a cgo package are gathered from their preprocessed
+			// form, whose positions map back to the original source.
+			if cgoShim(p, file) {
+				continue
+			}
+
 			for _, decl := range file.Decls {
-				if decl, ok := decl.(*ast.FuncDecl); ok {
+				// Init functions are called when their package is
+				// imported, and main functions are entry points: they
+				// are never reported.
+				if decl, ok := decl.(*ast.FuncDecl); ok && !isEntrypoint(p, decl) {
 					obj := p.TypesInfo.Defs[decl.Name].(*types.Func)
 					fn := prog.FuncValue(obj)
 					sourceFuncs = append(sourceFuncs, fn)
+
+					lines[p.Fset.Position(decl.Name.Pos())] = declLines(p.Fset, decl.Doc, decl)
+
+					if settings.Doc && decl.Doc != nil {
+						docs[p.Fset.Position(decl.Name.Pos())] = truncateDoc(decl.Doc.Text())
+					}
 				}
 			}
 
+			if testFlag && strings.HasSuffix(p.Fset.File(file.Pos()).Name(), "_test.go") {
+				roots = append(roots, testFuncs(prog, p, file)...)
+			}
+
+			if settings.AsmReachable && hasAsm(p) {
+				roots = append(roots, asmFuncs(prog, p, file)...)
+			}
+
+			if len(testTags) > 0 && hasBuildTag(file, testTags) {
+				roots = append(roots, exportedFuncs(prog, p, file)...)
+			}
+
+			sourceFuncVars = append(sourceFuncVars, funcVars(p, file, assigned, reads)...)
+
+			if settings.StrictVars {
+				vars.add(p, file)
+			}
+
+			if settings.Consts {
+				sourceConsts = append(sourceConsts, constDecls(p, file)...)
+				constUses(p, file, uses)
+			}
+
+			lookupSymbols(p, file, symbols)
+
+			sup.addDirectives(p, file)
+
+			if settings.DeadPackages {
+				blankImports(file, blank)
+			}
+
+			if settings.DeadFiles {
+				f := newSourceFile(p, file)
+				files[Rel(f.posn.Filename)] = f
+			}
+
 			if ast.IsGenerated(file) {
 				generated[p.Fset.File(file.Pos()).Name()] = true
+
+				if settings.Verbose {
+					generatedRefs(p, file, genRefs)
+				}
 			}
 		}
 	})
 
+	roots = append(roots, symbolFuncs(pkgs, symbols)...)
+
+	if len(roots) == 0 {
+		return nil, errors.New("no find main packages or tests")
+	}
+
+	// Packages are live when the program uses them, before adding the
+	// roots of every package implementing the interfaces below.
+	var live map[string]bool
+	if settings.DeadPackages {
+		live = livePackages(roots)
+	}
+
+	// The fmt package calls the methods of these interfaces through
+	// reflection when formatting values.
+	roots = append(roots, interfaceMethods(prog, pkgs, fmtInterfaces(prog))...)
+
+	if settings.FailOnEmpty && !slices.ContainsFunc(sourceFuncs, func(fn *ssa.Function) bool {
+		return filter == nil || filter.MatchString(fn.Pkg.Pkg.Path())
+	}) {
+		return nil, errors.New("no find functions to analyze")
+	}
+
+	if settings.ListEntrypoints {
+		listEntrypoints(prog, roots)
+	}
+
 	// Compute the reachabilty from main.
+	progress.phase("running RTA from %d roots over %d functions...", len(roots), len(sourceFuncs))
 	res := rta.Analyze(roots, false)
 
 	reachablePosn := make(map[token.Position]bool)
@@ -179,6 +709,98 @@ func runAnalysis(settings Settings) ([]Issue, error) {
 		}
 	}
 
+	// Func vars are unused when no reachable code reads them, unless they
+	// are assigned another value somewhere. Their func literal is always
+	// reachable for RTA, as its address is stored.
+	var funcVarIssues []Issue
+	seenVars := make(map[token.Position]bool)
+	for _, v := range sourceFuncVars {
+		if seenVars[v.posn] || assigned[v.posn] || generated[v.posn.Filename] {
+			continue
+		}
+		seenVars[v.posn] = true // suppress dups with same pos
+
+		if slices.ContainsFunc(reads[v.posn], func(fn token.Position) bool {
+			return !fn.IsValid() || reachablePosn[fn]
+		}) {
+			continue
+		}
+
+		if filter != nil && !filter.MatchString(v.pkgpath) {
+			continue
+		}
+
+		funcVarIssues = append(funcVarIssues, Issue{
+			Kind:       KindFuncVar,
+			Package:    v.pkgpath,
+			PkgName:    v.pkgname,
+			Func:       v.name,
+			Filename:   Rel(v.posn.Filename),
+			Line:       v.posn.Line,
+			Confidence: ConfidenceHigh,
+			Lines:      v.lines,
+		})
+	}
+
+	// Constants are unused when they are only used by unreachable functions.
+	var constIssues []Issue
+	seenConsts := make(map[token.Position]bool)
+	for _, c := range sourceConsts {
+		if seenConsts[c.posn] || generated[c.posn.Filename] || filter != nil && !filter.MatchString(c.pkgpath) {
+			continue
+		}
+		seenConsts[c.posn] = true
+
+		var users []string
+		used := false
+		for _, use := range uses[c.posn] {
+			if use.fn == "" || reachablePosn[use.posn] {
+				used = true
+				break
+			}
+			if !slices.Contains(users, use.fn) {
+				users = append(users, use.fn)
+			}
+		}
+		if used {
+			continue
+		}
+
+		var notes []string
+		if len(users) > 0 {
+			notes = append(notes, fmt.Sprintf("only used by unused %s", strings.Join(users, ", ")))
+		}
+
+		constIssues = append(constIssues, Issue{
+			Kind:       KindConst,
+			Package:    c.pkgpath,
+			PkgName:    c.pkgname,
+			Func:       c.name,
+			Filename:   Rel(c.posn.Filename),
+			Line:       c.posn.Line,
+			Confidence: ConfidenceHigh,
+			Notes:      notes,
+			Lines:      c.lines,
+		})
+	}
+
+	// Functions only stored in unread variables are reachable for RTA, as
+	// their address is taken.
+	var storedFuncs []*ssa.Function
+	if settings.StrictVars {
+		stored := vars.storedOnly()
+		for _, fn := range sourceFuncs {
+			if posn := prog.Fset.Position(fn.Pos()); reachablePosn[posn] && stored[posn] {
+				storedFuncs = append(storedFuncs, fn)
+				delete(stored, posn) // suppress dups with same pos
+			}
+		}
+	}
+
+	if settings.DumpSourceFuncs {
+		dumpSourceFuncs(prog, sourceFuncs, reachablePosn)
+	}
+
 	// Group unreachable functions by package path.
 	byPkgPath := make(map[string]map[*ssa.Function]bool)
 	for _, fn := range sourceFuncs {
@@ -197,33 +819,581 @@ func runAnalysis(settings Settings) ([]Issue, error) {
 		}
 	}
 
-	// Build array of jsonPackage objects.
-	var issues []Issue
-	for pkgpath := range maps.Keys(byPkgPath) {
+	// funcIssue returns the issue of the unused function fn.
+	funcIssue := func(fn *ssa.Function) Issue {
+		pkgpath := fn.Pkg.Pkg.Path()
+		pos := prog.Fset.Position(fn.Pos())
+
+		confidence := ConfidenceHigh
+		if fn.Signature.Recv() != nil && conventional[fn.Name()] {
+			confidence = ConfidenceLow
+		}
+
+		var notes []string
+		if files := ignored[pkgpath][fn.Name()]; len(files) > 0 {
+			notes = append(notes, fmt.Sprintf("referenced from files excluded by build constraints (%s)", strings.Join(files, ", ")))
+		}
+		if files := genRefs[pos]; len(files) > 0 {
+			notes = append(notes, fmt.Sprintf("referenced from dead code in generated files (%s)", strings.Join(files, ", ")))
+		}
+		if local, ok := shadows[pkgpath][fn.Name()]; ok && fn.Signature.Recv() == nil {
+			notes = append(notes, fmt.Sprintf("shadowed by local `%s` at %s:%d", fn.Name(), Rel(local.Filename), local.Line))
+		}
+
+		kind, recv := KindFunc, ""
+		if fn.Signature.Recv() != nil {
+			kind = KindMethod
+			if _, named := ReceiverNamed(fn.Signature.Recv()); named != nil {
+				recv = named.Obj().Name()
+			}
+		}
+
+		return Issue{
+			Kind:       kind,
+			Package:    pkgpath,
+			PkgName:    fn.Pkg.Pkg.Name(),
+			Recv:       recv,
+			Func:       fn.Name(),
+			Filename:   Rel(pos.Filename),
+			Line:       pos.Line,
+			Confidence: confidence,
+			Notes:      notes,
+			Doc:        docs[pos],
+			Lines:      lines[pos],
+		}
+	}
+
+	progress.phase("reporting unreachable functions of %d packages...", len(byPkgPath))
+
+	// Build the issues of each package in parallel, then sort them.
+	var (
+		mu     sync.Mutex
+		issues []Issue
+	)
+
+	workers := settings.Concurrency
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	var g errgroup.Group
+	g.SetLimit(workers)
+	for pkgpath, m := range byPkgPath {
 		if filter != nil && !filter.MatchString(pkgpath) {
 			continue
 		}
 
-		m := byPkgPath[pkgpath]
+		g.Go(func() error {
+			var pkgIssues []Issue
+			for fn := range maps.Keys(m) {
+				pos := prog.Fset.Position(fn.Pos())
 
-		for fn := range maps.Keys(m) {
-			pos := prog.Fset.Position(fn.Pos())
+				if generated[pos.Filename] {
+					continue
+				}
 
-			if generated[pos.Filename] {
-				continue
+				pkgIssues = append(pkgIssues, funcIssue(fn))
 			}
 
-			issues = append(issues, Issue{
-				Func:     fn.Name(),
-				Filename: Rel(pos.Filename),
-				Line:     pos.Line,
-			})
+			mu.Lock()
+			issues = append(issues, pkgIssues...)
+			mu.Unlock()
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	for _, fn := range storedFuncs {
+		pos := prog.Fset.Position(fn.Pos())
+		if generated[pos.Filename] || filter != nil && !filter.MatchString(fn.Pkg.Pkg.Path()) {
+			continue
+		}
+
+		issue := funcIssue(fn)
+		issue.Notes = append(issue.Notes, "only stored in package variables never read")
+		issues = append(issues, issue)
+	}
+
+	issues = append(issues, funcVarIssues...)
+	issues = append(issues, constIssues...)
+
+	issues = slices.DeleteFunc(issues, func(issue Issue) bool {
+		return binaries[issue.Package]
+	})
+
+	if settings.DeadPackages {
+		issues = deadPackages(issues, srcPkgs, blank, live)
+	}
+
+	// Sort issues so that the output does not depend on scheduling.
+	slices.SortFunc(issues, func(a, b Issue) int {
+		return cmp.Or(
+			cmp.Compare(a.Filename, b.Filename),
+			cmp.Compare(a.Line, b.Line),
+			cmp.Compare(a.Func, b.Func),
+		)
+	})
+
+	// The exported API is used by definition, including its constants and
+	// func vars, which are not roots.
+	issues = slices.DeleteFunc(issues, func(issue Issue) bool {
+		return apis[issue.Package] && token.IsExported(issue.Func) &&
+			(issue.Recv == "" || token.IsExported(issue.Recv))
+	})
+
+	var reported, suppressed []Issue
+	for _, issue := range issues {
+		if issue.Suppressed = sup.reason(issue); issue.Suppressed == "" {
+			reported = append(reported, issue)
+		} else if settings.ReportSuppressed {
+			suppressed = append(suppressed, issue)
+		}
+	}
+	issues = reported
+
+	if settings.Baseline != "" && settings.UpdateBaseline {
+		if err := writeBaseline(settings.Baseline, issues); err != nil {
+			return nil, err
+		}
+		issues = nil
+	}
+
+	if settings.DeadFiles {
+		issues = deadFiles(issues, files)
+	}
+
+	progress.phase("done: %d issues", len(issues))
+
+	if settings.Stats {
+		var nlines int
+		for _, issue := range issues {
+			nlines += issue.Lines
+		}
+		fmt.Fprintf(os.Stderr, "deadcode %s (%s, settings %s): %d packages, %d roots, %d functions, %d issues (%d lines) in %v\n",
+			Version, runtime.Version(), settings.hash(), npkgs, len(roots), len(sourceFuncs), len(issues), nlines, time.Since(start).Round(time.Millisecond))
+	}
+
+	if settings.DeletionOrder {
+		printDeletionOrder(prog, sourceFuncs, issues)
+	}
+
+	issues = append(issues, suppressed...)
+
+	if settings.ShowIDs {
+		for i := range issues {
+			issues[i].ID = issues[i].id()
+		}
+	}
+
+	if settings.Verbose {
+		for i := range issues {
+			issues[i].Hint = issues[i].hint()
 		}
 	}
 
 	return issues, nil
 }
 
+// listEntrypoints prints the roots of the analysis.
+func listEntrypoints(prog *ssa.Program, roots []*ssa.Function) {
+	seen := make(map[*ssa.Function]bool)
+	for _, fn := range roots {
+		if seen[fn] {
+			continue
+		}
+		seen[fn] = true
+
+		if posn := prog.Fset.Position(fn.Pos()); posn.IsValid() {
+			fmt.Fprintf(os.Stderr, "%s:%d: %s\n", Rel(posn.Filename), posn.Line, fn)
+		} else {
+			fmt.Fprintf(os.Stderr, "-: %s\n", fn)
+		}
+	}
+}
+
+// dumpSourceFuncs prints every gathered source function and whether it is reachable.
+func dumpSourceFuncs(prog *ssa.Program, sourceFuncs []*ssa.Function, reachablePosn map[token.Position]bool) {
+	seen := make(map[token.Position]bool)
+	for _, fn := range sourceFuncs {
+		posn := prog.Fset.Position(fn.Pos())
+		if seen[posn] {
+			continue
+		}
+		seen[posn] = true
+
+		status := "unreachable"
+		if reachablePosn[posn] {
+			status = "reachable"
+		}
+		fmt.Fprintf(os.Stderr, "%s:%d: %s %s\n", Rel(posn.Filename), posn.Line, fn, status)
+	}
+}
+
+// declLines returns the number of lines of node, including its doc comment.
+func declLines(fset *token.FileSet, doc *ast.CommentGroup, node ast.Node) int {
+	start := node.Pos()
+	if doc != nil {
+		start = doc.Pos()
+	}
+	return fset.Position(node.End()).Line - fset.Position(start).Line + 1
+}
+
+// maxDocLen is the maximum number of characters of a doc comment in an issue.
+const maxDocLen = 120
+
+// truncateDoc returns doc on a single line, truncated to maxDocLen characters.
+func truncateDoc(doc string) string {
+	doc = strings.Join(strings.Fields(doc), " ")
+	if utf8.RuneCountInString(doc) <= maxDocLen {
+		return doc
+	}
+	return string([]rune(doc)[:maxDocLen-1]) + "…"
+}
+
+// hash returns a short digest identifying the effective settings.
+func (s Settings) hash() string {
+	data, _ := json.Marshal(s)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:4])
+}
+
+// initialModules returns the quoted paths of the modules of the initial packages.
+func initialModules(initial []*packages.Package) []string {
+	var modules []string
+	seen := make(map[string]bool)
+	for _, p := range initial {
+		if p.Module == nil || p.Module.Path == "" || seen[p.Module.Path] {
+			continue
+		}
+		seen[p.Module.Path] = true
+		modules = append(modules, regexp.QuoteMeta(p.Module.Path))
+	}
+	return modules
+}
+
+// generatedRefs adds to refs the name of file for each function it refers to,
+// by position of the function.
+func generatedRefs(p *packages.Package, file *ast.File, refs map[token.Position][]string) {
+	name := filepath.Base(p.Fset.File(file.Pos()).Name())
+	seen := make(map[*types.Func]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		id, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+
+		if obj, ok := p.TypesInfo.Uses[id].(*types.Func); ok && !seen[obj.Origin()] {
+			seen[obj.Origin()] = true
+			posn := p.Fset.Position(obj.Origin().Pos())
+			if !slices.Contains(refs[posn], name) {
+				refs[posn] = append(refs[posn], name)
+			}
+		}
+		return true
+	})
+}
+
+// localDecls adds to decls the first position of each name declared in a
+// local scope of p, which shadows package-level declarations of that name.
+func localDecls(p *packages.Package, decls map[string]token.Position) map[string]token.Position {
+	if decls == nil {
+		decls = make(map[string]token.Position)
+	}
+
+	for id, obj := range p.TypesInfo.Defs {
+		if obj == nil || obj.Parent() == nil || obj.Parent() == p.Types.Scope() || id.Name == "_" {
+			continue
+		}
+
+		posn := p.Fset.Position(id.Pos())
+		if prev, ok := decls[id.Name]; !ok || posn.Filename < prev.Filename ||
+			posn.Filename == prev.Filename && posn.Offset < prev.Offset {
+			decls[id.Name] = posn
+		}
+	}
+	return decls
+}
+
+// hasBuildTag reports whether the //go:build constraint of file uses one of tags.
+func hasBuildTag(file *ast.File, tags map[string]bool) bool {
+	expr := fileConstraint(file)
+	return expr != nil && usesTag(expr, tags)
+}
+
+// usesTag reports whether expr requires one of tags, in any of its terms.
+func usesTag(expr constraint.Expr, tags map[string]bool) bool {
+	switch expr := expr.(type) {
+	case *constraint.TagExpr:
+		return tags[expr.Tag]
+	case *constraint.AndExpr:
+		return usesTag(expr.X, tags) || usesTag(expr.Y, tags)
+	case *constraint.OrExpr:
+		return usesTag(expr.X, tags) || usesTag(expr.Y, tags)
+	}
+	return false
+}
+
+// exportedFuncs returns the exported, non-generic functions declared in file.
+func exportedFuncs(prog *ssa.Program, p *packages.Package, file *ast.File) []*ssa.Function {
+	var fns []*ssa.Function
+	for _, decl := range file.Decls {
+		decl, ok := decl.(*ast.FuncDecl)
+		if !ok || decl.Recv != nil || !decl.Name.IsExported() || decl.Type.TypeParams != nil {
+			continue
+		}
+
+		obj := p.TypesInfo.Defs[decl.Name].(*types.Func)
+		fns = append(fns, prog.FuncValue(obj))
+	}
+	return fns
+}
+
+// hasAsm reports whether p has assembly files.
+func hasAsm(p *packages.Package) bool {
+	for _, filename := range p.OtherFiles {
+		if strings.HasSuffix(filename, ".s") {
+			return true
+		}
+	}
+	return false
+}
+
+// asmFuncs returns the functions declared without body in file, which are
+// implemented in assembly.
+func asmFuncs(prog *ssa.Program, p *packages.Package, file *ast.File) []*ssa.Function {
+	var fns []*ssa.Function
+	for _, decl := range file.Decls {
+		if decl, ok := decl.(*ast.FuncDecl); ok && decl.Body == nil && decl.Type.TypeParams == nil {
+			obj := p.TypesInfo.Defs[decl.Name].(*types.Func)
+			fns = append(fns, prog.FuncValue(obj))
+		}
+	}
+	return fns
+}
+
+// testFuncs returns the tests, benchmarks, examples and fuzz tests declared in file.
+func testFuncs(prog *ssa.Program, p *packages.Package, file *ast.File) []*ssa.Function {
+	var fns []*ssa.Function
+	for _, decl := range file.Decls {
+		decl, ok := decl.(*ast.FuncDecl)
+		if !ok || decl.Recv != nil || decl.Type.TypeParams != nil {
+			continue
+		}
+
+		for _, prefix := range []string{"Test", "Benchmark", "Example", "Fuzz"} {
+			if isTestName(decl.Name.Name, prefix) {
+				obj := p.TypesInfo.Defs[decl.Name].(*types.Func)
+				fns = append(fns, prog.FuncValue(obj))
+				break
+			}
+		}
+	}
+	return fns
+}
+
+// isTestName reports whether name is prefix followed by nothing or by a
+// character which is not a lower-case letter, as go test requires.
+func isTestName(name, prefix string) bool {
+	rest, ok := strings.CutPrefix(name, prefix)
+	if !ok {
+		return false
+	}
+	r, _ := utf8.DecodeRuneInString(rest)
+	return rest == "" || !unicode.IsLower(r)
+}
+
+// isEntrypoint reports whether decl is an init function, or the main
+// function of a main package.
+func isEntrypoint(p *packages.Package, decl *ast.FuncDecl) bool {
+	return decl.Recv == nil && (decl.Name.Name == "init" || decl.Name.Name == "main" && p.Name == "main")
+}
+
+// hasPathPrefix reports whether the import path s is one of prefixes, or
+// a package below one of them.
+func hasPathPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if s == prefix || strings.HasPrefix(s, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// exportedAPI returns the exported functions of pkg and the exported methods
+// of its exported types. Generic ones are returned in their generic form,
+// whose body calls what every instantiation calls.
+func exportedAPI(prog *ssa.Program, pkg *ssa.Package) []*ssa.Function {
+	var fns []*ssa.Function
+	for name, member := range pkg.Members {
+		if !token.IsExported(name) {
+			continue
+		}
+
+		switch member := member.(type) {
+		case *ssa.Function:
+			fns = append(fns, member)
+		case *ssa.Type:
+			named, ok := member.Type().(*types.Named)
+			if !ok || types.IsInterface(named) {
+				continue
+			}
+
+			// The method sets of generic types are only known once
+			// instantiated: take their declared methods.
+			if named.TypeParams().Len() > 0 {
+				for i := range named.NumMethods() {
+					if m := named.Method(i); m.Exported() {
+						fns = append(fns, prog.FuncValue(m))
+					}
+				}
+				continue
+			}
+
+			mset := prog.MethodSets.MethodSet(types.NewPointer(named))
+			for i := range mset.Len() {
+				if sel := mset.At(i); sel.Obj().Exported() {
+					fns = append(fns, prog.MethodValue(sel))
+				}
+			}
+		}
+	}
+	return fns
+}
+
+// exportedInterfaces returns the exported interfaces declared in non-main pkgs.
+func exportedInterfaces(pkgs []*ssa.Package) []*types.Interface {
+	var ifaces []*types.Interface
+	for _, pkg := range pkgs {
+		if pkg == nil || pkg.Pkg.Name() == "main" {
+			continue
+		}
+
+		scope := pkg.Pkg.Scope()
+		for _, name := range scope.Names() {
+			obj, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || !obj.Exported() {
+				continue
+			}
+
+			named, ok := obj.Type().(*types.Named)
+			if !ok || named.TypeParams().Len() > 0 {
+				continue
+			}
+
+			if iface, ok := named.Underlying().(*types.Interface); ok && iface.NumMethods() > 0 {
+				ifaces = append(ifaces, iface)
+			}
+		}
+	}
+	return ifaces
+}
+
+// fmtInterfaces returns fmt.Formatter, fmt.Stringer and fmt.GoStringer, or
+// nil if the program doesn't import fmt.
+func fmtInterfaces(prog *ssa.Program) []*types.Interface {
+	pkg := prog.ImportedPackage("fmt")
+	if pkg == nil {
+		return nil
+	}
+
+	var ifaces []*types.Interface
+	for _, name := range []string{"Formatter", "Stringer", "GoStringer"} {
+		if obj, ok := pkg.Pkg.Scope().Lookup(name).(*types.TypeName); ok {
+			if iface, ok := obj.Type().Underlying().(*types.Interface); ok {
+				ifaces = append(ifaces, iface)
+			}
+		}
+	}
+	return ifaces
+}
+
+// interfaceMethods returns the methods of the named types declared in pkgs
+// that implement one of ifaces.
+func interfaceMethods(prog *ssa.Program, pkgs []*ssa.Package, ifaces []*types.Interface) []*ssa.Function {
+	var fns []*ssa.Function
+	for _, pkg := range pkgs {
+		if pkg == nil {
+			continue
+		}
+
+		for _, member := range pkg.Members {
+			typ, ok := member.(*ssa.Type)
+			if !ok {
+				continue
+			}
+
+			named, ok := typ.Type().(*types.Named)
+			if !ok || named.TypeParams().Len() > 0 || types.IsInterface(named) {
+				continue
+			}
+
+			ptr := types.NewPointer(named)
+			mset := prog.MethodSets.MethodSet(ptr)
+			for _, iface := range ifaces {
+				if !types.Implements(ptr, iface) {
+					continue
+				}
+
+				for i := range iface.NumMethods() {
+					m := iface.Method(i)
+					if sel := mset.Lookup(m.Pkg(), m.Name()); sel != nil {
+						fns = append(fns, prog.MethodValue(sel))
+					}
+				}
+			}
+		}
+	}
+	return fns
+}
+
+// ignoredRefs returns the files excluded by build constraints
+// that mention each identifier, other than in a func declaration or
+// qualified by an imported package.
+func ignoredRefs(filenames []string) map[string][]string {
+	refs := make(map[string][]string)
+	fset := token.NewFileSet()
+	for _, filename := range filenames {
+		if !strings.HasSuffix(filename, ".go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, filename, nil, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+
+		imports := make(map[string]bool)
+		for _, spec := range file.Imports {
+			if spec.Name != nil {
+				imports[spec.Name.Name] = true
+			} else if importPath, err := strconv.Unquote(spec.Path.Value); err == nil {
+				imports[path.Base(importPath)] = true
+			}
+		}
+
+		skip := make(map[*ast.Ident]bool)
+		seen := make(map[string]bool)
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncDecl:
+				skip[n.Name] = true
+			case *ast.SelectorExpr:
+				if x, ok := n.X.(*ast.Ident); ok && imports[x.Name] {
+					skip[n.Sel] = true
+				}
+			case *ast.Ident:
+				if !skip[n] && !seen[n.Name] {
+					seen[n.Name] = true
+					refs[n.Name] = append(refs[n.Name], filepath.Base(filename))
+				}
+			}
+			return true
+		})
+	}
+	return refs
+}
+
 // Rel returns a relative path.
 func Rel(filename string) string {
 	if rel, err := filepath.Rel(cwd, filename); err == nil {
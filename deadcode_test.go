@@ -0,0 +1,283 @@
+package deadcode
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// toolchain loads the fixtures: x/tools can't build SSA of the standard
+// library of later Go versions.
+const toolchain = "go1.24.4"
+
+// testdata is the directory of the fixtures.
+var testdata, _ = filepath.Abs("testdata")
+
+// analyze runs the analysis of the module testdata/fixture, and returns its
+// issues as "file:line: message".
+func analyze(t testing.TB, fixture string, settings Settings) []string {
+	t.Helper()
+
+	issues, err := analyzeIssues(t, fixture, settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, issue := range issues {
+		got = append(got, fmt.Sprintf("%s:%d: %s", filepath.ToSlash(issue.Filename), issue.Line, issue.message()))
+	}
+	return got
+}
+
+// analyzeIssues runs the analysis of the module testdata/fixture.
+func analyzeIssues(t testing.TB, fixture string, settings Settings) ([]Issue, error) {
+	t.Helper()
+	t.Setenv("GOTOOLCHAIN", toolchain)
+
+	dir := filepath.Join(testdata, fixture)
+
+	wd := cwd
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	cwd = dir
+	t.Cleanup(func() {
+		cwd = wd
+		_ = os.Chdir(wd)
+	})
+
+	return Analyze(settings)
+}
+
+// assertIssues checks that got are the issues of want, in order.
+func assertIssues(t *testing.T, got []string, want ...string) {
+	t.Helper()
+
+	if !slices.Equal(got, want) {
+		t.Errorf("got issues:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestLibraryInternal(t *testing.T) {
+	got := analyze(t, "library", Settings{LibraryInternal: true})
+	assertIssues(t, got,
+		"generic/generic.go:13: func `unused` is unused",
+		"side/side.go:11: func `unused` is unused",
+	)
+}
+
+func TestBinary(t *testing.T) {
+	assertIssues(t, analyze(t, "binaries", Settings{}))
+
+	got := analyze(t, "binaries", Settings{Binary: "example.com/binaries/cmd/a"})
+	assertIssues(t, got,
+		"lib/lib.go:5: func `ForRoot` is unused",
+	)
+}
+
+func TestRootPackagePrefixes(t *testing.T) {
+	assertIssues(t, analyze(t, "api", Settings{Consts: true}),
+		"api/api.go:3: const `Version` is unused",
+		"api/api.go:5: const `internal` is unused",
+		"api/api.go:9: func var `hook` is unused",
+		"api/api.go:13: func `Exported` is unused",
+		"api/api.go:15: func `helper` is unused",
+		"api/v1/v1.go:3: func `Exported` is unused",
+		"api/v1/v1.go:5: func `Map` is unused",
+		"api/v1/v1.go:7: func `helper` is unused",
+		"apix/apix.go:3: func `Exported` is unused",
+	)
+
+	for _, prefix := range []string{"example.com/api/api", "example.com/api/api/"} {
+		got := analyze(t, "api", Settings{Consts: true, RootPackagePrefixes: []string{prefix}})
+		assertIssues(t, got,
+			"api/api.go:5: const `internal` is unused",
+			"api/api.go:9: func var `hook` is unused",
+			"api/api.go:15: func `helper` is unused",
+			"apix/apix.go:3: func `Exported` is unused",
+		)
+	}
+}
+
+func TestExportedInterfaces(t *testing.T) {
+	assertIssues(t, analyze(t, "interfaces", Settings{}),
+		"store/store.go:13: method `cache.Get` is unused",
+	)
+
+	assertIssues(t, analyze(t, "interfaces", Settings{ExportedInterfaces: true}))
+}
+
+func TestTestTags(t *testing.T) {
+	assertIssues(t, analyze(t, "testtags", Settings{}),
+		"release.go:5: func `releaseOnly` is unused",
+	)
+
+	// Files constrained by !testonly are excluded from the main build.
+	got := analyze(t, "testtags", Settings{TestTags: []string{"testonly"}})
+	assertIssues(t, got,
+		"support.go:10: func `stale` is unused",
+	)
+}
+
+func TestPluginSymbols(t *testing.T) {
+	assertIssues(t, analyze(t, "plugins", Settings{}),
+		"handler/handler.go:9: func `Reload` is unused",
+		"handler/handler.go:11: func `unused` is unused",
+	)
+
+	got := analyze(t, "plugins", Settings{PluginSymbols: []string{"Reload"}})
+	assertIssues(t, got,
+		"handler/handler.go:11: func `unused` is unused",
+	)
+}
+
+func TestGoExperiment(t *testing.T) {
+	assertIssues(t, analyze(t, "experiments", Settings{}),
+		"noarenas.go:7: func `withoutArenas` is unused",
+	)
+
+	assertIssues(t, analyze(t, "experiments", Settings{GoExperiment: "arenas"}),
+		"arenas.go:7: func `arenasOnly` is unused",
+	)
+}
+
+func TestIgnoredFiles(t *testing.T) {
+	// os.Open in legacy.go doesn't refer to Open.
+	got := analyze(t, "constrained", Settings{Verbose: true})
+	assertIssues(t, got,
+		"main.go:5: func `Open` is unused (hint: delete the function)",
+		"main.go:7: func `legacyOpen` is unused: referenced from files excluded by build constraints (legacy.go) (hint: delete the function)",
+	)
+}
+
+func TestDefaultFilter(t *testing.T) {
+	got := analyze(t, "tools", Settings{})
+	assertIssues(t, got,
+		"main.go:7: func `unused` is unused",
+	)
+}
+
+func TestDefer(t *testing.T) {
+	got := analyze(t, "deferred", Settings{})
+	assertIssues(t, got,
+		"main.go:14: func `dead` is unused",
+		"main.go:18: func `deadCleanup` is unused",
+	)
+}
+
+func TestMethodSets(t *testing.T) {
+	// V.size is only called through *V, with the wrapper of the pointer
+	// method set.
+	got := analyze(t, "methodsets", Settings{})
+	assertIssues(t, got,
+		"main.go:9: method `V.unused` is unused",
+		"main.go:15: method `P.unusedPtr` is unused",
+	)
+}
+
+func TestMainPackageFiles(t *testing.T) {
+	got := analyze(t, "multifile", Settings{})
+	assertIssues(t, got,
+		"format.go:9: func `obsoleteFormat` is unused",
+		"main.go:7: func `obsolete` is unused",
+		"run.go:5: func `runOld` is unused",
+	)
+}
+
+func TestInterfaceAssertion(t *testing.T) {
+	got := analyze(t, "assertions", Settings{})
+	assertIssues(t, got,
+		"main.go:7: method `T.Close` is unused",
+	)
+}
+
+func TestEmbedded(t *testing.T) {
+	got := analyze(t, "embedded", Settings{})
+	assertIssues(t, got,
+		"main.go:12: method `mem.put` is unused",
+		"main.go:19: method `cached.put` is unused",
+		"main.go:21: method `cached.flush` is unused",
+		"main.go:27: method `base.bye` is unused",
+	)
+}
+
+func TestGenericMethods(t *testing.T) {
+	// Get is only called on Cache[string, int], Never on none.
+	got := analyze(t, "generics", Settings{})
+	assertIssues(t, got,
+		"main.go:9: method `Cache.Never` is unused",
+	)
+}
+
+func TestSharedTestHelpers(t *testing.T) {
+	got := analyze(t, "testutil", Settings{Test: true})
+	assertIssues(t, got,
+		"testutil/testutil.go:5: func `Unused` is unused",
+	)
+}
+
+func TestInterfaceVars(t *testing.T) {
+	// Exported methods of types converted to interfaces may be called
+	// through reflection.
+	got := analyze(t, "ifacevars", Settings{})
+	assertIssues(t, got,
+		"main.go:18: method `assigned.stop` is unused",
+	)
+}
+
+func TestFormatters(t *testing.T) {
+	got := analyze(t, "formatters", Settings{})
+	assertIssues(t, got,
+		"main.go:17: method `size.kilo` is unused",
+	)
+}
+
+func TestConcurrency(t *testing.T) {
+	for _, fixture := range []string{"orphans", "api", "embedded"} {
+		want := analyze(t, fixture, Settings{Concurrency: 1, Consts: true})
+		for _, workers := range []int{2, 8} {
+			got := analyze(t, fixture, Settings{Concurrency: workers, Consts: true})
+			if !slices.Equal(got, want) {
+				t.Errorf("%s: got issues with %d workers:\n%q\nwant:\n%q", fixture, workers, got, want)
+			}
+		}
+	}
+}
+
+// BenchmarkConcurrency measures the whole analysis, of which building the
+// issues is only a part.
+func BenchmarkConcurrency(b *testing.B) {
+	for _, workers := range []int{1, 4} {
+		b.Run(fmt.Sprint(workers), func(b *testing.B) {
+			for range b.N {
+				if _, err := analyzeIssues(b, "orphans", Settings{Concurrency: workers}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestOverlay(t *testing.T) {
+	assertIssues(t, analyze(t, "overlay", Settings{}),
+		"main.go:7: func `unused` is unused",
+	)
+
+	src := `package main
+
+func main() { used(); unused() }
+
+func used() {}
+
+func unused() {}
+
+func overlayOnly() {}
+`
+	overlay := map[string][]byte{filepath.Join(testdata, "overlay", "main.go"): []byte(src)}
+	assertIssues(t, analyze(t, "overlay", Settings{Overlay: overlay}),
+		"main.go:9: func `overlayOnly` is unused",
+	)
+}
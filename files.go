@@ -0,0 +1,119 @@
+package deadcode
+
+import (
+	"go/ast"
+	"go/build/constraint"
+	"go/token"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// sourceFile describes the top-level declarations of a file.
+type sourceFile struct {
+	posn  token.Position // of the package clause
+	funcs int            // number of func declarations
+	other bool           // has declarations other than funcs and imports
+	gated bool           // has build constraints
+	lines int
+}
+
+// newSourceFile describes file.
+func newSourceFile(p *packages.Package, file *ast.File) sourceFile {
+	f := sourceFile{
+		posn:  p.Fset.Position(file.Package),
+		lines: p.Fset.File(file.Pos()).LineCount(),
+		gated: fileConstraint(file) != nil || platformSpecific(p.Fset.File(file.Pos()).Name()),
+	}
+
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			f.funcs++
+		case *ast.GenDecl:
+			if decl.Tok != token.IMPORT {
+				f.other = true
+			}
+		}
+	}
+	return f
+}
+
+// deadFiles replaces the issues of the files of which every declaration is
+// unused by a single issue for the file.
+func deadFiles(issues []Issue, files map[string]sourceFile) []Issue {
+	unused := make(map[string]int)
+	for _, issue := range issues {
+		if issue.Kind == KindFunc || issue.Kind == KindMethod {
+			unused[issue.Filename]++
+		}
+	}
+
+	dead := make(map[string]bool)
+	for filename, f := range files {
+		if f.funcs > 0 && !f.other && !f.gated && unused[filename] == f.funcs {
+			dead[filename] = true
+		}
+	}
+
+	var result []Issue
+	for _, issue := range issues {
+		if !dead[issue.Filename] {
+			result = append(result, issue)
+			continue
+		}
+
+		if f, ok := files[issue.Filename]; ok {
+			delete(files, issue.Filename)
+			result = append(result, Issue{
+				Kind:       KindFile,
+				Package:    issue.Package,
+				PkgName:    issue.PkgName,
+				Func:       filepath.Base(issue.Filename),
+				Filename:   issue.Filename,
+				Line:       f.posn.Line,
+				Confidence: ConfidenceHigh,
+				Lines:      f.lines,
+			})
+		}
+	}
+	return result
+}
+
+// cgoShim reports whether file is generated by cgo, as _cgo_gotypes.go,
+// rather than the preprocessed form of one of the Go files of p. The files
+// are in the build cache in both cases, but only the preprocessed ones have
+// positions mapping back to the Go files.
+func cgoShim(p *packages.Package, file *ast.File) bool {
+	return !slices.Contains(p.GoFiles, p.Fset.Position(file.Package).Filename)
+}
+
+// fileConstraint returns the //go:build constraint of file, if any.
+func fileConstraint(file *ast.File) constraint.Expr {
+	for _, group := range file.Comments {
+		if group.Pos() >= file.Package {
+			break
+		}
+
+		for _, comment := range group.List {
+			if !constraint.IsGoBuild(comment.Text) {
+				continue
+			}
+
+			if expr, err := constraint.Parse(comment.Text); err == nil {
+				return expr
+			}
+		}
+	}
+	return nil
+}
+
+// platformSpecific reports whether the name of filename restricts it to the
+// current GOOS or GOARCH, as in foo_linux.go or foo_amd64_test.go.
+func platformSpecific(filename string) bool {
+	name := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(filename), ".go"), "_test")
+	return strings.HasSuffix(name, "_"+runtime.GOOS) || strings.HasSuffix(name, "_"+runtime.GOARCH)
+}
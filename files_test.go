@@ -0,0 +1,31 @@
+package deadcode
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func TestCgo(t *testing.T) {
+	if _, err := exec.LookPath("cc"); err != nil {
+		t.Skip("cgo requires a C compiler")
+	}
+
+	got := analyze(t, "cgo", Settings{})
+	assertIssues(t, got,
+		"main.go:10: func `unused` is unused",
+	)
+}
+
+func TestDeadFilesIDs(t *testing.T) {
+	issues, err := analyzeIssues(t, "files", Settings{DeadFiles: true, ShowIDs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(issues) != 2 || issues[0].Kind != KindFile || issues[1].Kind != KindFile {
+		t.Fatalf("got issues %+v, want a and b util.go", issues)
+	}
+	if issues[0].ID == issues[1].ID {
+		t.Errorf("got ID %s for both %s and %s", issues[0].ID, issues[0].Filename, issues[1].Filename)
+	}
+}
@@ -0,0 +1,108 @@
+package deadcode
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// funcVar is a package-level variable initialized with a func literal.
+type funcVar struct {
+	name    string
+	pkgpath string
+	pkgname string
+	posn    token.Position // of the variable
+	lines   int
+}
+
+// funcVars returns the package-level variables of file initialized with a
+// func literal, except the exported ones of non-main packages. It records
+// in assigned the package-level variables the file assigns to, and in reads
+// the position of the funcs reading them, invalid outside funcs.
+func funcVars(p *packages.Package, file *ast.File, assigned map[token.Position]bool, reads map[token.Position][]token.Position) []funcVar {
+	var vars []funcVar
+	for _, decl := range file.Decls {
+		decl, ok := decl.(*ast.GenDecl)
+		if !ok || decl.Tok != token.VAR {
+			continue
+		}
+
+		for _, spec := range decl.Specs {
+			spec := spec.(*ast.ValueSpec)
+			if len(spec.Names) != len(spec.Values) {
+				continue
+			}
+
+			for i, name := range spec.Names {
+				if _, ok := spec.Values[i].(*ast.FuncLit); !ok || name.Name == "_" {
+					continue
+				}
+
+				// Exported variables of libraries are hooks, which tests
+				// and other modules may reassign.
+				if name.IsExported() && p.Name != "main" {
+					continue
+				}
+
+				v := funcVar{
+					name:    name.Name,
+					pkgpath: p.PkgPath,
+					pkgname: p.Name,
+					posn:    p.Fset.Position(name.Pos()),
+					lines:   declLines(p.Fset, spec.Doc, spec),
+				}
+				if len(decl.Specs) == 1 && len(spec.Names) == 1 {
+					v.lines = declLines(p.Fset, decl.Doc, decl)
+				}
+				vars = append(vars, v)
+			}
+		}
+	}
+
+	for _, decl := range file.Decls {
+		var fn token.Position
+		if decl, ok := decl.(*ast.FuncDecl); ok {
+			fn = p.Fset.Position(decl.Name.Pos())
+		}
+
+		lhs := make(map[*ast.Ident]bool)
+		ast.Inspect(decl, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.AssignStmt:
+				if n.Tok != token.ASSIGN {
+					return true
+				}
+
+				for _, expr := range n.Lhs {
+					id, ok := ast.Unparen(expr).(*ast.Ident)
+					if !ok {
+						continue
+					}
+
+					lhs[id] = true
+					if v := packageVar(p, id); v != nil {
+						assigned[p.Fset.Position(v.Pos())] = true
+					}
+				}
+			case *ast.Ident:
+				if v := packageVar(p, n); v != nil && !lhs[n] {
+					posn := p.Fset.Position(v.Pos())
+					reads[posn] = append(reads[posn], fn)
+				}
+			}
+			return true
+		})
+	}
+
+	return vars
+}
+
+// packageVar returns the package-level variable id refers to, if any.
+func packageVar(p *packages.Package, id *ast.Ident) *types.Var {
+	if v, ok := p.TypesInfo.Uses[id].(*types.Var); ok && v.Pkg() != nil && v.Parent() == v.Pkg().Scope() {
+		return v
+	}
+	return nil
+}
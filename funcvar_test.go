@@ -0,0 +1,14 @@
+package deadcode
+
+import "testing"
+
+func TestFuncVars(t *testing.T) {
+	got := analyze(t, "funcvars", Settings{})
+	assertIssues(t, got,
+		"clock/clock.go:7: func var `tick` is unused",
+		"main.go:7: func var `uncalled` is unused",
+		"main.go:13: func var `Exported` is unused",
+		"main.go:25: func var `onlyDead` is unused",
+		"main.go:27: func `dead` is unused",
+	)
+}
@@ -4,10 +4,9 @@ go 1.23.0
 
 require (
 	github.com/golangci/plugin-module-register v0.1.1
+	golang.org/x/sync v0.13.0
 	golang.org/x/tools v0.32.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
-require (
-	golang.org/x/mod v0.24.0 // indirect
-	golang.org/x/sync v0.13.0 // indirect
-)
+require golang.org/x/mod v0.24.0 // indirect
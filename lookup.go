@@ -0,0 +1,64 @@
+package deadcode
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+)
+
+// lookupSymbols adds to symbols the string constants passed to
+// (*plugin.Plugin).Lookup in file.
+func lookupSymbols(p *packages.Package, file *ast.File, symbols map[string]bool) {
+	if p.Imports["plugin"] == nil {
+		return
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 1 {
+			return true
+		}
+
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Lookup" || !isPluginRecv(p.TypesInfo.Selections[sel]) {
+			return true
+		}
+
+		if tv := p.TypesInfo.Types[call.Args[0]]; tv.Value != nil && tv.Value.Kind() == constant.String {
+			symbols[constant.StringVal(tv.Value)] = true
+		}
+		return true
+	})
+}
+
+// isPluginRecv reports whether sel selects a method of plugin.Plugin.
+func isPluginRecv(sel *types.Selection) bool {
+	if sel == nil || sel.Kind() != types.MethodVal {
+		return false
+	}
+
+	_, named := ReceiverNamed(sel.Obj().(*types.Func).Signature().Recv())
+	return named != nil && named.Obj().Pkg() != nil &&
+		named.Obj().Pkg().Path() == "plugin" && named.Obj().Name() == "Plugin"
+}
+
+// symbolFuncs returns the functions of the main packages among pkgs named
+// as symbols. Plugins are main packages, usually without a main function.
+func symbolFuncs(pkgs []*ssa.Package, symbols map[string]bool) []*ssa.Function {
+	var fns []*ssa.Function
+	for _, pkg := range pkgs {
+		if pkg == nil || pkg.Pkg.Name() != "main" {
+			continue
+		}
+
+		for name := range symbols {
+			if fn := pkg.Func(name); fn != nil && ast.IsExported(name) {
+				fns = append(fns, fn)
+			}
+		}
+	}
+	return fns
+}
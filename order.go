@@ -0,0 +1,120 @@
+package deadcode
+
+import (
+	"fmt"
+	"go/token"
+	"os"
+	"slices"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// deletionOrder returns the unused functions of issues ordered so that each
+// function comes before the unused functions it references: deleting them
+// in this order never breaks the build in between.
+func deletionOrder(prog *ssa.Program, sourceFuncs []*ssa.Function, issues []Issue) []Issue {
+	byPosn := make(map[token.Position]*ssa.Function)
+	for _, fn := range sourceFuncs {
+		byPosn[prog.Fset.Position(fn.Pos())] = fn
+	}
+
+	// Index the unused functions by position.
+	dead := make(map[token.Position]int)
+	for i, issue := range issues {
+		if issue.Kind == KindFunc || issue.Kind == KindMethod {
+			dead[token.Position{Filename: issue.Filename, Line: issue.Line}] = i
+		}
+	}
+
+	key := func(fn *ssa.Function) (int, bool) {
+		if fn.Origin() != nil {
+			fn = fn.Origin()
+		}
+		posn := prog.Fset.Position(fn.Pos())
+		i, ok := dead[token.Position{Filename: Rel(posn.Filename), Line: posn.Line}]
+		return i, ok
+	}
+
+	// Count the references from unused functions to each unused function.
+	refs := make(map[int][]int)
+	indegree := make(map[int]int)
+	for posn, fn := range byPosn {
+		from, ok := dead[token.Position{Filename: Rel(posn.Filename), Line: posn.Line}]
+		if !ok {
+			continue
+		}
+
+		for _, callee := range references(fn) {
+			if to, ok := key(callee); ok && to != from && !slices.Contains(refs[from], to) {
+				refs[from] = append(refs[from], to)
+				indegree[to]++
+			}
+		}
+	}
+
+	// Unused functions are sorted by position, so follow their order.
+	var queue, order []int
+	for _, i := range dead {
+		if indegree[i] == 0 {
+			queue = append(queue, i)
+		}
+	}
+	slices.Sort(queue)
+
+	done := make(map[int]bool)
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		done[i] = true
+		order = append(order, i)
+
+		for _, to := range refs[i] {
+			if indegree[to]--; indegree[to] == 0 {
+				queue = append(queue, to)
+			}
+		}
+	}
+
+	// Functions in reference cycles can be deleted together in any order.
+	var cycles []int
+	for _, i := range dead {
+		if !done[i] {
+			cycles = append(cycles, i)
+		}
+	}
+	slices.Sort(cycles)
+
+	var result []Issue
+	for _, i := range append(order, cycles...) {
+		result = append(result, issues[i])
+	}
+	return result
+}
+
+// references returns the functions referenced by fn and its closures.
+func references(fn *ssa.Function) []*ssa.Function {
+	var fns []*ssa.Function
+	var rands []*ssa.Value
+	for _, b := range fn.Blocks {
+		for _, instr := range b.Instrs {
+			for _, rand := range instr.Operands(rands[:0]) {
+				if f, ok := (*rand).(*ssa.Function); ok {
+					fns = append(fns, f)
+				}
+			}
+		}
+	}
+
+	for _, anon := range fn.AnonFuncs {
+		fns = append(fns, references(anon)...)
+	}
+	return fns
+}
+
+// printDeletionOrder prints the positions of the unused functions of issues
+// in deletion order.
+func printDeletionOrder(prog *ssa.Program, sourceFuncs []*ssa.Function, issues []Issue) {
+	for _, issue := range deletionOrder(prog, sourceFuncs, issues) {
+		fmt.Fprintf(os.Stderr, "%s:%d: %s\n", issue.Filename, issue.Line, issue.name())
+	}
+}
@@ -0,0 +1,108 @@
+package deadcode
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+)
+
+// sourcePackage describes a package which may be reported as a whole.
+type sourcePackage struct {
+	name  string
+	posn  token.Position // of the package clause of its first file
+	lines int
+}
+
+// newSourcePackage describes p.
+func newSourcePackage(p *packages.Package) (sourcePackage, bool) {
+	pkg := sourcePackage{name: p.Name}
+	for _, file := range p.Syntax {
+		if cgoShim(p, file) {
+			continue
+		}
+
+		if !pkg.posn.IsValid() {
+			pkg.posn = p.Fset.Position(file.Package)
+		}
+		pkg.lines += p.Fset.File(file.Pos()).LineCount()
+	}
+	return pkg, pkg.posn.IsValid()
+}
+
+// blankImports adds the paths of the packages imported by file for their
+// side effects to blank.
+func blankImports(file *ast.File, blank map[string]bool) {
+	for _, spec := range file.Imports {
+		if spec.Name == nil || spec.Name.Name != "_" {
+			continue
+		}
+
+		if path, err := strconv.Unquote(spec.Path.Value); err == nil {
+			blank[path] = true
+		}
+	}
+}
+
+// livePackages returns the paths of the packages of roots, and of the
+// packages they import: every reachable function is in one of them.
+func livePackages(roots []*ssa.Function) map[string]bool {
+	live := make(map[string]bool)
+
+	var visit func(pkg *types.Package)
+	visit = func(pkg *types.Package) {
+		if live[pkg.Path()] {
+			return
+		}
+		live[pkg.Path()] = true
+
+		for _, imp := range pkg.Imports() {
+			visit(imp)
+		}
+	}
+
+	for _, fn := range roots {
+		if origin := fn.Origin(); origin != nil {
+			fn = origin
+		}
+
+		if fn.Pkg != nil {
+			visit(fn.Pkg.Pkg)
+		}
+	}
+	return live
+}
+
+// deadPackages replaces the issues of the packages which are not live by a
+// single issue for the package. Packages imported for their
+// side effects are never reported as a whole.
+func deadPackages(issues []Issue, pkgs map[string]sourcePackage, blank, live map[string]bool) []Issue {
+	var result []Issue
+	dead := make(map[string]bool)
+	for path, pkg := range pkgs {
+		if live[path] || blank[path] {
+			continue
+		}
+
+		dead[path] = true
+		result = append(result, Issue{
+			Kind:       KindPackage,
+			Package:    path,
+			PkgName:    pkg.name,
+			Filename:   Rel(pkg.posn.Filename),
+			Line:       pkg.posn.Line,
+			Confidence: ConfidenceHigh,
+			Lines:      pkg.lines,
+		})
+	}
+
+	for _, issue := range issues {
+		if !dead[issue.Package] {
+			result = append(result, issue)
+		}
+	}
+	return result
+}
@@ -0,0 +1,13 @@
+package deadcode
+
+import "testing"
+
+func TestDeadPackages(t *testing.T) {
+	got := analyze(t, "orphans", Settings{DeadPackages: true})
+	assertIssues(t, got,
+		"a/a.go:5: func `dead` is unused",
+		"o/o.go:1: package `example.com/orphans/o` is unused",
+		"o2/o2.go:1: package `example.com/orphans/o2` is unused",
+		"s/s.go:3: func `Unused` is unused",
+	)
+}
@@ -0,0 +1,44 @@
+package deadcode
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+)
+
+// profile runs analyze, writing the CPU and heap profiles requested by settings.
+func profile(settings Settings, analyze func(Settings) ([]Issue, error)) ([]Issue, error) {
+	if settings.CPUProfile != "" {
+		f, err := os.Create(settings.CPUProfile)
+		if err != nil {
+			return nil, fmt.Errorf("create cpu profile: %v", err)
+		}
+		defer f.Close()
+
+		if err := pprof.StartCPUProfile(f); err != nil {
+			return nil, fmt.Errorf("start cpu profile: %v", err)
+		}
+		defer pprof.StopCPUProfile()
+	}
+
+	issues, err := analyze(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	if settings.MemProfile != "" {
+		f, err := os.Create(settings.MemProfile)
+		if err != nil {
+			return nil, fmt.Errorf("create memory profile: %v", err)
+		}
+		defer f.Close()
+
+		runtime.GC()
+		if err := pprof.WriteHeapProfile(f); err != nil {
+			return nil, fmt.Errorf("write memory profile: %v", err)
+		}
+	}
+
+	return issues, nil
+}
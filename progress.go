@@ -0,0 +1,23 @@
+package deadcode
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// progress prints the phases of the analysis to stderr, when enabled.
+type progress struct {
+	enabled bool
+	start   time.Time
+}
+
+// phase prints the start of a phase with the time elapsed since the start
+// of the analysis.
+func (p progress) phase(format string, args ...any) {
+	if !p.enabled {
+		return
+	}
+	elapsed := time.Since(p.start).Round(time.Millisecond)
+	fmt.Fprintf(os.Stderr, "deadcode: %8v "+format+"\n", append([]any{elapsed}, args...)...)
+}
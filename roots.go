@@ -0,0 +1,46 @@
+package deadcode
+
+import (
+	"maps"
+	"slices"
+	"sync"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// RootProvider supplies roots of the analysis, such as the functions a
+// framework calls by reflection.
+type RootProvider interface {
+	Roots(prog *ssa.Program, pkgs []*ssa.Package) []*ssa.Function
+}
+
+var (
+	providersMu sync.RWMutex
+	providers   = make(map[string]RootProvider)
+)
+
+// RegisterRootProvider registers a root provider, usually from the init
+// function of its package.
+func RegisterRootProvider(name string, p RootProvider) {
+	providersMu.Lock()
+
+	providers[name] = p
+
+	providersMu.Unlock()
+}
+
+// providedRoots returns the roots supplied by the registered providers.
+func providedRoots(prog *ssa.Program, pkgs []*ssa.Package) []*ssa.Function {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+
+	var roots []*ssa.Function
+	for _, name := range slices.Sorted(maps.Keys(providers)) {
+		for _, fn := range providers[name].Roots(prog, pkgs) {
+			if fn != nil {
+				roots = append(roots, fn)
+			}
+		}
+	}
+	return roots
+}
@@ -0,0 +1,140 @@
+package deadcode
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// varSpec is the declaration of package-level variables with values.
+type varSpec struct {
+	filename   string
+	start, end int // offsets
+	vars       []token.Position
+}
+
+// contains reports whether posn is within the spec.
+func (s *varSpec) contains(posn token.Position) bool {
+	return posn.Filename == s.filename && s.start <= posn.Offset && posn.Offset < s.end
+}
+
+// storage records the package-level variables and the references to
+// functions, to find the functions only stored in variables never read.
+type storage struct {
+	specs map[token.Position]*varSpec         // by position of the first variable
+	read  map[token.Position]bool             // variables read, by position
+	refs  map[token.Position][]token.Position // references by function position
+}
+
+func newStorage() *storage {
+	return &storage{
+		specs: make(map[token.Position]*varSpec),
+		read:  make(map[token.Position]bool),
+		refs:  make(map[token.Position][]token.Position),
+	}
+}
+
+// add records the variable declarations of file, the variables it reads
+// and the functions it references.
+func (s *storage) add(p *packages.Package, file *ast.File) {
+	for _, decl := range file.Decls {
+		decl, ok := decl.(*ast.GenDecl)
+		if !ok || decl.Tok != token.VAR {
+			continue
+		}
+
+		for _, spec := range decl.Specs {
+			spec := spec.(*ast.ValueSpec)
+			if len(spec.Values) == 0 {
+				continue
+			}
+
+			start, end := p.Fset.Position(spec.Pos()), p.Fset.Position(spec.End())
+			vs := &varSpec{filename: start.Filename, start: start.Offset, end: end.Offset}
+			for _, name := range spec.Names {
+				vs.vars = append(vs.vars, p.Fset.Position(name.Pos()))
+			}
+			s.specs[vs.vars[0]] = vs
+		}
+	}
+
+	// Assigning a variable is not reading it.
+	assigned := make(map[*ast.Ident]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if stmt, ok := n.(*ast.AssignStmt); ok && stmt.Tok == token.ASSIGN {
+			for _, lhs := range stmt.Lhs {
+				if id, ok := ast.Unparen(lhs).(*ast.Ident); ok {
+					assigned[id] = true
+				}
+			}
+		}
+		return true
+	})
+
+	for _, decl := range file.Decls {
+		// References of a function to itself don't keep it alive.
+		var self types.Object
+		if decl, ok := decl.(*ast.FuncDecl); ok {
+			self = p.TypesInfo.Defs[decl.Name]
+		}
+
+		ast.Inspect(decl, func(n ast.Node) bool {
+			id, ok := n.(*ast.Ident)
+			if !ok {
+				return true
+			}
+
+			switch obj := p.TypesInfo.Uses[id].(type) {
+			case *types.Var:
+				if !assigned[id] && obj.Pkg() != nil && obj.Parent() == obj.Pkg().Scope() {
+					s.read[p.Fset.Position(obj.Pos())] = true
+				}
+			case *types.Func:
+				if obj.Signature().Recv() == nil && obj.Origin() != self {
+					posn := p.Fset.Position(obj.Origin().Pos())
+					s.refs[posn] = append(s.refs[posn], p.Fset.Position(id.Pos()))
+				}
+			}
+			return true
+		})
+	}
+}
+
+// storedOnly returns the positions of the functions only referenced from
+// the values of package-level variables never read.
+func (s *storage) storedOnly() map[token.Position]bool {
+	var unread []*varSpec
+	for _, spec := range s.specs {
+		read := false
+		for _, v := range spec.vars {
+			read = read || s.read[v]
+		}
+		if !read {
+			unread = append(unread, spec)
+		}
+	}
+
+	stored := make(map[token.Position]bool)
+	for fn, refs := range s.refs {
+		all := true
+		for _, ref := range refs {
+			in := false
+			for _, spec := range unread {
+				if spec.contains(ref) {
+					in = true
+					break
+				}
+			}
+			if !in {
+				all = false
+				break
+			}
+		}
+		if all {
+			stored[fn] = true
+		}
+	}
+	return stored
+}
@@ -0,0 +1,37 @@
+package deadcode
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestStrictVars(t *testing.T) {
+	assertIssues(t, analyze(t, "strictvars", Settings{}))
+
+	got := analyze(t, "strictvars", Settings{StrictVars: true})
+	assertIssues(t, got,
+		"main.go:11: func `f` is unused: only stored in package variables never read",
+		"main.go:13: func `g` is unused: only stored in package variables never read",
+	)
+
+	src := `package main
+
+func main() {
+	for _, fn := range keepAlive {
+		run(fn)
+	}
+}
+
+// run makes the functions of type func() reachable when their address is
+// taken.
+func run(fn func()) { fn() }
+
+var keepAlive = []func(){f, g}
+
+func f() {}
+
+func g() {}
+`
+	overlay := map[string][]byte{filepath.Join(testdata, "strictvars", "main.go"): []byte(src)}
+	assertIssues(t, analyze(t, "strictvars", Settings{StrictVars: true, Overlay: overlay}))
+}
@@ -0,0 +1,232 @@
+package deadcode
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// ignoreDirective suppresses the issue of the declaration it documents.
+const ignoreDirective = "//deadcode:ignore"
+
+// Suppression reasons, in order of precedence.
+const (
+	SuppressedDirective = "directive"
+	SuppressedWhitelist = "whitelist"
+	SuppressedExclude   = "exclude"
+	SuppressedBaseline  = "baseline"
+)
+
+// suppressor decides which issues are suppressed, and why.
+type suppressor struct {
+	directives map[string]map[int]bool // lines of ignored declarations by file
+	whitelist  map[string]bool
+	receivers  map[string]bool
+	exclude    *regexp.Regexp
+	paths      []*regexp.Regexp
+	baseline   map[string]bool
+}
+
+// newSuppressor returns a suppressor for settings.
+func newSuppressor(settings Settings) (*suppressor, error) {
+	s := &suppressor{
+		directives: make(map[string]map[int]bool),
+		whitelist:  make(map[string]bool),
+		receivers:  make(map[string]bool),
+	}
+
+	for _, name := range settings.Whitelist {
+		s.whitelist[name] = true
+	}
+	for _, name := range settings.ExcludeReceivers {
+		s.receivers[name] = true
+	}
+
+	if settings.Exclude != "" {
+		exclude, err := regexp.Compile(settings.Exclude)
+		if err != nil {
+			return nil, fmt.Errorf("failed create exclude: %v", err)
+		}
+		s.exclude = exclude
+	}
+
+	if settings.ExcludePathsFile != "" {
+		paths, err := readExcludePaths(settings.ExcludePathsFile)
+		if err != nil {
+			return nil, err
+		}
+		s.paths = paths
+	}
+
+	if settings.Baseline != "" && !settings.UpdateBaseline {
+		baseline, err := readBaseline(settings.Baseline)
+		if err != nil {
+			return nil, err
+		}
+		s.baseline = baseline
+	}
+
+	return s, nil
+}
+
+// addDirectives records the declarations of file documented by an ignore directive.
+func (s *suppressor) addDirectives(p *packages.Package, file *ast.File) {
+	add := func(doc *ast.CommentGroup, pos token.Pos) {
+		if !hasDirective(doc) {
+			return
+		}
+
+		posn := p.Fset.Position(pos)
+		filename := Rel(posn.Filename)
+		if s.directives[filename] == nil {
+			s.directives[filename] = make(map[int]bool)
+		}
+		s.directives[filename][posn.Line] = true
+	}
+
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			add(decl.Doc, decl.Name.Pos())
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				spec, ok := spec.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+
+				doc := spec.Doc
+				if doc == nil && len(decl.Specs) == 1 {
+					doc = decl.Doc
+				}
+				for _, name := range spec.Names {
+					add(doc, name.Pos())
+				}
+			}
+		}
+	}
+}
+
+// reason returns why issue is suppressed, or "" if it is not. When several
+// mechanisms apply, the first in order of precedence is returned.
+func (s *suppressor) reason(issue Issue) string {
+	switch {
+	case s.directives[issue.Filename][issue.Line]:
+		return SuppressedDirective
+	case s.whitelist[issue.Func] || s.whitelist[issue.name()] || s.whitelist[issue.key()] || s.excludedReceiver(issue):
+		return SuppressedWhitelist
+	case s.exclude != nil && s.exclude.MatchString(issue.Package) || s.excludedPath(issue.Filename):
+		return SuppressedExclude
+	case s.baseline[issue.key()]:
+		return SuppressedBaseline
+	}
+	return ""
+}
+
+// excludedReceiver reports whether issue is a method of an excluded receiver
+// type, named as `T`, `pkg.T` or `path/to/pkg.T`.
+func (s *suppressor) excludedReceiver(issue Issue) bool {
+	if issue.Recv == "" {
+		return false
+	}
+	return s.receivers[issue.Recv] ||
+		s.receivers[issue.PkgName+"."+issue.Recv] ||
+		s.receivers[issue.Package+"."+issue.Recv]
+}
+
+// excludedPath reports whether filename matches one of the excluded paths.
+func (s *suppressor) excludedPath(filename string) bool {
+	filename = filepath.ToSlash(filename)
+	for _, path := range s.paths {
+		if path.MatchString(filename) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasDirective reports whether doc contains the ignore directive.
+func hasDirective(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+
+	for _, comment := range doc.List {
+		if comment.Text == ignoreDirective || strings.HasPrefix(comment.Text, ignoreDirective+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// readExcludePaths reads the path regular expressions listed in filename.
+func readExcludePaths(filename string) ([]*regexp.Regexp, error) {
+	lines, err := readLines(filename)
+	if err != nil {
+		return nil, fmt.Errorf("read exclude paths: %v", err)
+	}
+
+	var paths []*regexp.Regexp
+	for _, line := range lines {
+		path, err := regexp.Compile(line)
+		if err != nil {
+			return nil, fmt.Errorf("failed create exclude path: %v", err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// readLines returns the lines of filename, except blank ones and # comments.
+func readLines(filename string) ([]string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// readBaseline reads the keys of the issues stored in filename.
+func readBaseline(filename string) (map[string]bool, error) {
+	lines, err := readLines(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read baseline: %v", err)
+	}
+
+	baseline := make(map[string]bool)
+	for _, line := range lines {
+		baseline[line] = true
+	}
+	return baseline, nil
+}
+
+// writeBaseline stores the keys of issues in filename.
+func writeBaseline(filename string, issues []Issue) error {
+	var b strings.Builder
+	for _, issue := range issues {
+		b.WriteString(issue.key())
+		b.WriteByte('\n')
+	}
+	return os.WriteFile(filename, []byte(b.String()), 0o644)
+}
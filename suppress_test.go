@@ -0,0 +1,75 @@
+package deadcode
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExcludeReceivers(t *testing.T) {
+	assertIssues(t, analyze(t, "receivers", Settings{}),
+		"impl/impl.go:7: method `S.unused` is unused",
+		"main.go:7: method `T.unusedPtr` is unused",
+	)
+
+	got := analyze(t, "receivers", Settings{ExcludeReceivers: []string{"main.T", "service.S"}})
+	assertIssues(t, got)
+
+	got = analyze(t, "receivers", Settings{ExcludeReceivers: []string{"v2.T", "impl.S"}})
+	assertIssues(t, got,
+		"impl/impl.go:7: method `S.unused` is unused",
+		"main.go:7: method `T.unusedPtr` is unused",
+	)
+
+	got = analyze(t, "receivers", Settings{ExcludeReceivers: []string{"example.com/receivers/v2.T", "T"}})
+	assertIssues(t, got,
+		"impl/impl.go:7: method `S.unused` is unused",
+	)
+}
+
+func TestSuppressionReasons(t *testing.T) {
+	settings := Settings{
+		Whitelist:        []string{"ignored", "whitelisted", "legacy.old"},
+		Exclude:          "legacy",
+		ExcludePathsFile: "paths.txt",
+		Baseline:         "baseline.txt",
+	}
+	assertIssues(t, analyze(t, "suppress", settings),
+		"main.go:16: func `reported` is unused",
+	)
+
+	// Each issue is suppressed by the first mechanism in order of
+	// precedence: directive, whitelist, exclude, baseline.
+	settings.ReportSuppressed = true
+	assertIssues(t, analyze(t, "suppress", settings),
+		"main.go:16: func `reported` is unused",
+		"excluded.go:3: func `excludedPath` is unused (suppressed: exclude)",
+		"legacy/legacy.go:5: func `old` is unused (suppressed: exclude)",
+		"main.go:10: func `ignored` is unused (suppressed: directive)",
+		"main.go:12: func `whitelisted` is unused (suppressed: whitelist)",
+		"main.go:14: func `baselined` is unused (suppressed: baseline)",
+	)
+}
+
+func TestUpdateBaseline(t *testing.T) {
+	baseline := filepath.Join(t.TempDir(), "baseline.txt")
+
+	got := analyze(t, "suppress", Settings{Baseline: baseline, UpdateBaseline: true})
+	assertIssues(t, got)
+
+	// Issues ignored by a directive stay ignored without the baseline.
+	data, err := os.ReadFile(baseline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "example.com/suppress.excludedPath\n" +
+		"example.com/suppress/legacy.old\n" +
+		"example.com/suppress.whitelisted\n" +
+		"example.com/suppress.baselined\n" +
+		"example.com/suppress.reported\n"
+	if string(data) != want {
+		t.Errorf("got baseline:\n%s\nwant:\n%s", data, want)
+	}
+
+	assertIssues(t, analyze(t, "suppress", Settings{Baseline: baseline}))
+}
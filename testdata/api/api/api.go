@@ -0,0 +1,15 @@
+package api
+
+const Version = "v1"
+
+const internal = 1
+
+var Hook = func() {}
+
+var hook = func() {}
+
+func Do() {}
+
+func Exported() {}
+
+func helper() {}
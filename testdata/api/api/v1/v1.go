@@ -0,0 +1,7 @@
+package v1
+
+func Exported() {}
+
+func Map[T any](x T) T { return helper(x) }
+
+func helper[T any](x T) T { return x }
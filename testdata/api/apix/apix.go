@@ -0,0 +1,3 @@
+package apix
+
+func Exported() {}
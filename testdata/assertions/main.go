@@ -0,0 +1,18 @@
+package main
+
+type closer interface{ Close() error }
+
+type T struct{}
+
+func (*T) Close() error { return nil }
+
+var _ closer = (*T)(nil)
+
+type U struct{}
+
+func (U) Close() error { return nil }
+
+func main() {
+	var c closer = U{}
+	_ = c.Close()
+}
@@ -0,0 +1,5 @@
+package main
+
+import "example.com/binaries/lib"
+
+func main() { lib.ForA() }
@@ -0,0 +1,5 @@
+package lib
+
+func ForA() {}
+
+func ForRoot() {}
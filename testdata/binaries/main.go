@@ -0,0 +1,7 @@
+package main
+
+import "example.com/binaries/lib"
+
+func main() { run() }
+
+func run() { lib.ForRoot() }
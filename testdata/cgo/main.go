@@ -0,0 +1,10 @@
+package main
+
+// static int add(int a, int b) { return a + b; }
+import "C"
+
+func main() { _ = sum(1, 2) }
+
+func sum(a, b int) int { return int(C.add(C.int(a), C.int(b))) }
+
+func unused() {}
@@ -0,0 +1,10 @@
+//go:build legacy
+
+package main
+
+import "os"
+
+func init() {
+	legacyOpen()
+	_, _ = os.Open("legacy.txt")
+}
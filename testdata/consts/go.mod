@@ -0,0 +1,3 @@
+module example.com/consts
+
+go 1.22
@@ -0,0 +1,25 @@
+package main
+
+const (
+	A = iota
+	B
+	C
+)
+
+const (
+	Read  = 1 << iota
+	Write = 1 << iota
+)
+
+const (
+	one = 1
+	two = 2
+)
+
+const unused = "unused"
+
+const onlyDead = 3
+
+func main() { println(B, Write, two) }
+
+func dead() int { return onlyDead }
@@ -0,0 +1,18 @@
+package main
+
+func main() { run() }
+
+func run() {
+	defer cleanup()
+	defer func() { release() }()
+}
+
+func cleanup() {}
+
+func release() {}
+
+func dead() {
+	defer deadCleanup()
+}
+
+func deadCleanup() {}
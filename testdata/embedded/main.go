@@ -0,0 +1,36 @@
+package main
+
+type store interface {
+	get() int
+	put(int)
+}
+
+type mem struct{}
+
+func (mem) get() int { return 1 }
+
+func (mem) put(int) {}
+
+// cached embeds an interface, and overrides its methods.
+type cached struct{ store }
+
+func (c cached) get() int { return c.store.get() }
+
+func (cached) put(int) {}
+
+func (cached) flush() {}
+
+type base struct{}
+
+func (base) hello() {}
+
+func (base) bye() {}
+
+// outer embeds a struct.
+type outer struct{ base }
+
+func main() {
+	var s store = cached{store: mem{}}
+	_ = s.get()
+	outer{}.hello()
+}
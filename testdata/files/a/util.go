@@ -0,0 +1,5 @@
+package a
+
+func Helper() {}
+
+func helper() {}
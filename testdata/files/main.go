@@ -0,0 +1,8 @@
+package main
+
+import (
+	"example.com/files/a"
+	"example.com/files/b"
+)
+
+func main() { a.Used(); b.Used() }
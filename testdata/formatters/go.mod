@@ -0,0 +1,3 @@
+module example.com/formatters
+
+go 1.22
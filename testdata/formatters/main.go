@@ -0,0 +1,17 @@
+package main
+
+import "fmt"
+
+func main() { fmt.Println(report{Size: 1}) }
+
+// report is printed with its fields, which fmt formats through reflection.
+type report struct{ Size size }
+
+type size int
+
+// Format is only called by fmt.
+func (s size) Format(f fmt.State, verb rune) { fmt.Fprint(f, bytes(int(s))) }
+
+func bytes(n int) string { return fmt.Sprint(n, "B") }
+
+func (s size) kilo() int { return int(s) / 1024 }
@@ -0,0 +1,7 @@
+package clock
+
+var Now = func() int { return 0 }
+
+var Sleep = func() {}
+
+var tick = func() {}
@@ -0,0 +1,3 @@
+module example.com/funcvars
+
+go 1.22
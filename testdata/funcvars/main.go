@@ -0,0 +1,27 @@
+package main
+
+import "example.com/funcvars/clock"
+
+var called = func() {}
+
+var uncalled = func() {}
+
+var reassigned = func() {}
+
+var passed = func() {}
+
+var Exported = func() {}
+
+func init() { reassigned = func() {} }
+
+func main() {
+	called()
+	run(passed)
+	_ = clock.Now()
+}
+
+func run(f func()) { f() }
+
+var onlyDead = func() {}
+
+func dead() { onlyDead() }
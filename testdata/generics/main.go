@@ -0,0 +1,18 @@
+package main
+
+type Cache[K comparable, V any] struct{ m map[K]V }
+
+func (c *Cache[K, V]) Get(k K) V { return c.m[k] }
+
+func (c *Cache[K, V]) Put(k K, v V) { c.m[k] = v }
+
+func (c *Cache[K, V]) Never() int { return len(c.m) }
+
+func main() {
+	a := &Cache[string, int]{m: map[string]int{}}
+	a.Put("x", 1)
+	_ = a.Get("x")
+
+	b := &Cache[int, string]{m: map[int]string{}}
+	b.Put(1, "x")
+}
@@ -0,0 +1,3 @@
+module example.com/ifacevars
+
+go 1.22
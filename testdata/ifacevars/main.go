@@ -0,0 +1,24 @@
+package main
+
+type Handler interface{ Handle() int }
+
+type called struct{}
+
+func (called) Handle() int { return 1 }
+
+type Stopper interface {
+	Stop()
+	stop()
+}
+
+type assigned struct{}
+
+func (assigned) Stop() {}
+
+func (assigned) stop() {}
+
+var handler Handler = called{}
+
+var stopper Stopper = assigned{}
+
+func main() { _ = handler.Handle() }
@@ -0,0 +1,5 @@
+package main
+
+import "example.com/interfaces/store"
+
+func main() { store.Open() }
@@ -0,0 +1,13 @@
+package store
+
+// Store may be implemented and used outside the module.
+type Store interface {
+	Get(key string) string
+}
+
+type cache struct{}
+
+// Open doesn't return cache as a Store.
+func Open() { _ = cache{} }
+
+func (cache) Get(key string) string { return key }
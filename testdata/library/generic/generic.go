@@ -0,0 +1,13 @@
+package generic
+
+func Map[T any](x T) T { return identity(x) }
+
+func identity[T any](x T) T { return x }
+
+type List[T any] struct{ items []T }
+
+func (l *List[T]) Len() int { return count(l.items) }
+
+func count[T any](items []T) int { return len(items) }
+
+func unused() {}
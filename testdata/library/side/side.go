@@ -0,0 +1,11 @@
+package side
+
+var registered []string
+
+func init() { registered = append(registered, name()) }
+
+func name() string { return "side" }
+
+func Registered() []string { return registered }
+
+func unused() {}
@@ -0,0 +1,3 @@
+module example.com/methodsets
+
+go 1.22
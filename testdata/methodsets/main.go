@@ -0,0 +1,22 @@
+package main
+
+type sizer interface{ size() int }
+
+type V struct{}
+
+func (V) size() int { return 1 }
+
+func (V) unused() {}
+
+type P struct{}
+
+func (*P) size() int { return 2 }
+
+func (*P) unusedPtr() {}
+
+func main() {
+	var s sizer = &V{}
+	_ = s.size()
+	s = &P{}
+	_ = s.size()
+}
@@ -0,0 +1,9 @@
+package main
+
+func init() {}
+
+func setup() {}
+
+func format() {}
+
+func obsoleteFormat() {}
@@ -0,0 +1,7 @@
+package main
+
+func init() { setup() }
+
+func main() { run() }
+
+func obsolete() {}
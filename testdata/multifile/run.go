@@ -0,0 +1,5 @@
+package main
+
+func run() { format() }
+
+func runOld() { obsoleteFormat() }
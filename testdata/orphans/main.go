@@ -0,0 +1,11 @@
+package main
+
+import (
+	"fmt"
+
+	"example.com/orphans/a"
+	"example.com/orphans/c"
+	_ "example.com/orphans/s"
+)
+
+func main() { fmt.Println(a.A() + c.C) }
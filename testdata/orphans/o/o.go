@@ -0,0 +1,11 @@
+package o
+
+import "example.com/orphans/o2"
+
+func O() int { return o2.X() }
+
+var V = 1
+
+type X struct{}
+
+func (X) String() string { return "X" }
@@ -0,0 +1,3 @@
+package o2
+
+func X() int { return 1 }
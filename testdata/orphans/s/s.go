@@ -0,0 +1,3 @@
+package s
+
+func Unused() {}
@@ -0,0 +1,7 @@
+package main
+
+func main() { used() }
+
+func used() {}
+
+func unused() {}
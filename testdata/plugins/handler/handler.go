@@ -0,0 +1,11 @@
+// Package main is built with -buildmode=plugin.
+package main
+
+func Handler() { serve() }
+
+func serve() {}
+
+// Reload is looked up with a name computed at run time.
+func Reload() {}
+
+func unused() {}
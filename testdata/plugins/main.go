@@ -0,0 +1,13 @@
+package main
+
+import "plugin"
+
+func main() {
+	p, err := plugin.Open("handler.so")
+	if err != nil {
+		panic(err)
+	}
+	if _, err := p.Lookup("Handler"); err != nil {
+		panic(err)
+	}
+}
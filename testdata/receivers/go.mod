@@ -0,0 +1,3 @@
+module example.com/receivers/v2
+
+go 1.22
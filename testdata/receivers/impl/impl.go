@@ -0,0 +1,7 @@
+package service
+
+type S struct{}
+
+func New() *S { return &S{} }
+
+func (*S) unused() {}
@@ -0,0 +1,9 @@
+package main
+
+import service "example.com/receivers/v2/impl"
+
+type T struct{}
+
+func (*T) unusedPtr() {}
+
+func main() { service.New() }
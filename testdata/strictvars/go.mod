@@ -0,0 +1,3 @@
+module example.com/strictvars
+
+go 1.22
@@ -0,0 +1,13 @@
+package main
+
+func main() { run(func() {}) }
+
+// run makes the functions of type func() reachable when their address is
+// taken.
+func run(fn func()) { fn() }
+
+var keepAlive = []func(){f, g}
+
+func f() {}
+
+func g() {}
@@ -0,0 +1,5 @@
+package legacy
+
+func Used() {}
+
+func old() {}
@@ -0,0 +1,16 @@
+package main
+
+import "example.com/suppress/legacy"
+
+func main() { legacy.Used() }
+
+// ignored is suppressed by every mechanism.
+//
+//deadcode:ignore
+func ignored() {}
+
+func whitelisted() {}
+
+func baselined() {}
+
+func reported() {}
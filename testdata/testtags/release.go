@@ -0,0 +1,5 @@
+//go:build !testonly
+
+package main
+
+func releaseOnly() {}
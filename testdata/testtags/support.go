@@ -0,0 +1,10 @@
+//go:build testonly
+
+package main
+
+// Fixture is only called by tests built with the testonly tag.
+func Fixture() { helper() }
+
+func helper() {}
+
+func stale() {}
@@ -0,0 +1,13 @@
+package b
+
+import (
+	"testing"
+
+	"example.com/testutil/testutil"
+)
+
+func TestB(t *testing.T) {
+	if B()+testutil.Helper() != 1 {
+		t.Fail()
+	}
+}
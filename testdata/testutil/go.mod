@@ -0,0 +1,3 @@
+module example.com/testutil
+
+go 1.22
@@ -0,0 +1,5 @@
+package testutil
+
+func Helper() int { return 1 }
+
+func Unused() int { return 2 }
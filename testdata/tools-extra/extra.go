@@ -0,0 +1,5 @@
+package extra
+
+func Used() {}
+
+func unused() {}
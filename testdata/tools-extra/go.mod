@@ -0,0 +1,3 @@
+module example.com/tools-extra
+
+go 1.24
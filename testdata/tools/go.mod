@@ -0,0 +1,9 @@
+module example.com/tools
+
+go 1.24
+
+tool example.com/tools-extra/cmd/gen
+
+require example.com/tools-extra v0.0.0
+
+replace example.com/tools-extra => ../tools-extra
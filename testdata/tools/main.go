@@ -0,0 +1,7 @@
+package main
+
+import extra "example.com/tools-extra"
+
+func main() { extra.Used() }
+
+func unused() {}